@@ -2,6 +2,7 @@ package buildah
 
 import (
 	"archive/tar"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -19,9 +20,15 @@ import (
 	"github.com/containers/buildah/copier"
 	"github.com/containers/buildah/define"
 	"github.com/containers/buildah/pkg/chrootuser"
+	"github.com/containers/buildah/pkg/parse"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/pkg/blobinfocache/none"
+	"github.com/containers/image/v5/transports/alltransports"
+	"github.com/containers/image/v5/types"
 	"github.com/containers/storage/pkg/fileutils"
 	"github.com/containers/storage/pkg/idtools"
 	"github.com/hashicorp/go-multierror"
+	digest "github.com/opencontainers/go-digest"
 	"github.com/opencontainers/runc/libcontainer/userns"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
@@ -68,11 +75,25 @@ type AddAndCopyOptions struct {
 	// Clear the sticky bit on items being copied.  Has no effect on
 	// archives being extracted, where the bit is always preserved.
 	StripStickyBit bool
+	// Clear extended attributes, which is also how ACLs and Linux security
+	// capabilities are recorded, on items being copied.  Has no effect on
+	// archives being extracted, where they are always preserved.
+	StripXattrs bool
+	// SystemContext holds credentials and other configuration which we
+	// should use when fetching content from registries, such as when
+	// resolving "oci://" sources.
+	SystemContext *types.SystemContext
 }
 
 // sourceIsRemote returns true if "source" is a remote location.
 func sourceIsRemote(source string) bool {
-	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+	return strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "oci://")
+}
+
+// sourceIsOCIArtifact returns true if "source" refers to a blob in a
+// container registry, addressed by digest, rather than to a plain URL.
+func sourceIsOCIArtifact(source string) bool {
+	return strings.HasPrefix(source, "oci://")
 }
 
 // getURL writes a tar archive containing the named content
@@ -154,6 +175,118 @@ func getURL(src string, chown *idtools.IDPair, mountpoint, renameTarget string,
 	return errors.Wrapf(err, "error writing content from %q to tar stream", src)
 }
 
+// ociArtifactCacheDir returns the directory in which blobs fetched via
+// "oci://" sources are cached, keyed by their digest, so that multiple ADD
+// instructions naming the same digest don't repeatedly hit the registry.
+func ociArtifactCacheDir() string {
+	return filepath.Join(parse.GetTempDir(), "buildah-oci-artifacts")
+}
+
+// getOCIArtifact writes a tar archive containing the content of the blob
+// named by an "oci://registry/repository@digest" source, fetching it from
+// the registry (using the same authentication and TLS configuration as an
+// image pull) if it isn't already present in our local by-digest cache.
+func getOCIArtifact(ctx context.Context, sys *types.SystemContext, src string, chown *idtools.IDPair, mountpoint, renameTarget string, writer io.Writer, chmod *os.FileMode) error {
+	spec := strings.TrimPrefix(src, "oci://")
+	refString, digestString := spec, ""
+	if i := strings.LastIndex(spec, "@"); i != -1 {
+		refString, digestString = spec[:i], spec[i+1:]
+	}
+	if digestString == "" {
+		return errors.Errorf("error parsing %q: expected a repository reference followed by \"@\" and a digest", src)
+	}
+	blobDigest, err := digest.Parse(digestString)
+	if err != nil {
+		return errors.Wrapf(err, "error parsing digest in %q", src)
+	}
+	if _, err := reference.ParseNormalizedNamed(refString); err != nil {
+		return errors.Wrapf(err, "error parsing repository reference in %q", src)
+	}
+
+	cacheDir := ociArtifactCacheDir()
+	cachePath := filepath.Join(cacheDir, blobDigest.Encoded())
+
+	if _, err := os.Stat(cachePath); err != nil {
+		if !os.IsNotExist(err) {
+			return errors.Wrapf(err, "error checking for cached copy of %q", src)
+		}
+		if err := os.MkdirAll(cacheDir, 0700); err != nil {
+			return errors.Wrapf(err, "error creating cache directory %q", cacheDir)
+		}
+		ref, err := alltransports.ParseImageName("docker://" + refString)
+		if err != nil {
+			return errors.Wrapf(err, "error parsing %q as a reference to a registry repository", refString)
+		}
+		imgSrc, err := ref.NewImageSource(ctx, sys)
+		if err != nil {
+			return errors.Wrapf(err, "error contacting registry to fetch %q", refString)
+		}
+		defer imgSrc.Close()
+		blob, _, err := imgSrc.GetBlob(ctx, types.BlobInfo{Digest: blobDigest, Size: -1}, none.NoCache)
+		if err != nil {
+			return errors.Wrapf(err, "error fetching blob %q from %q", blobDigest, refString)
+		}
+		defer blob.Close()
+		f, err := ioutil.TempFile(cacheDir, "download")
+		if err != nil {
+			return errors.Wrapf(err, "error creating temporary file to hold %q", src)
+		}
+		defer f.Close()
+		verifier := blobDigest.Verifier()
+		if _, err := io.Copy(f, io.TeeReader(blob, verifier)); err != nil {
+			os.Remove(f.Name())
+			return errors.Wrapf(err, "error writing blob %q to temporary file %q", blobDigest, f.Name())
+		}
+		if !verifier.Verified() {
+			os.Remove(f.Name())
+			return errors.Errorf("error verifying contents of blob %q fetched from %q: digest mismatch", blobDigest, refString)
+		}
+		if err := os.Rename(f.Name(), cachePath); err != nil {
+			os.Remove(f.Name())
+			return errors.Wrapf(err, "error caching blob %q as %q", blobDigest, cachePath)
+		}
+	}
+
+	f, err := os.Open(cachePath)
+	if err != nil {
+		return errors.Wrapf(err, "error opening cached copy of %q", src)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "error checking size of cached copy of %q", src)
+	}
+
+	name := renameTarget
+	if name == "" {
+		name = blobDigest.Encoded()
+	}
+	tw := tar.NewWriter(writer)
+	defer tw.Close()
+	uid, gid := 0, 0
+	if chown != nil {
+		uid, gid = chown.UID, chown.GID
+	}
+	var mode int64 = 0600
+	if chmod != nil {
+		mode = int64(*chmod)
+	}
+	hdr := tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Size:     info.Size(),
+		Uid:      uid,
+		Gid:      gid,
+		Mode:     mode,
+		ModTime:  time.Unix(0, 0).UTC(),
+	}
+	if err := tw.WriteHeader(&hdr); err != nil {
+		return errors.Wrapf(err, "error writing header")
+	}
+	_, err = io.Copy(tw, f)
+	return errors.Wrapf(err, "error writing content from %q to tar stream", src)
+}
+
 // includeDirectoryAnyway returns true if "path" is a prefix for an exception
 // known to "pm".  If "path" is a directory that "pm" claims matches its list
 // of patterns, but "pm"'s list of exclusions contains a pattern for which
@@ -377,7 +510,11 @@ func (b *Builder) Add(destination string, extract bool, options AddAndCopyOption
 			pipeReader, pipeWriter := io.Pipe()
 			wg.Add(1)
 			go func() {
-				getErr = getURL(src, chownFiles, mountPoint, renameTarget, pipeWriter, chmodDirsFiles)
+				if sourceIsOCIArtifact(src) {
+					getErr = getOCIArtifact(context.Background(), options.SystemContext, src, chownFiles, mountPoint, renameTarget, pipeWriter, chmodDirsFiles)
+				} else {
+					getErr = getURL(src, chownFiles, mountPoint, renameTarget, pipeWriter, chmodDirsFiles)
+				}
 				pipeWriter.Close()
 				wg.Done()
 			}()
@@ -393,13 +530,14 @@ func (b *Builder) Add(destination string, extract bool, options AddAndCopyOption
 					_, putErr = io.Copy(hasher, pipeReader)
 				} else {
 					putOptions := copier.PutOptions{
-						UIDMap:        destUIDMap,
-						GIDMap:        destGIDMap,
-						ChownDirs:     nil,
-						ChmodDirs:     nil,
-						ChownFiles:    nil,
-						ChmodFiles:    nil,
-						IgnoreDevices: userns.RunningInUserNS(),
+						UIDMap:            destUIDMap,
+						GIDMap:            destGIDMap,
+						ChownDirs:         nil,
+						ChmodDirs:         nil,
+						ChownFiles:        nil,
+						ChmodFiles:        nil,
+						IgnoreDevices:     userns.RunningInUserNS(),
+						IgnoreXattrErrors: userns.RunningInUserNS(),
 					}
 					putErr = copier.Put(extractDirectory, extractDirectory, putOptions, io.TeeReader(pipeReader, hasher))
 				}
@@ -502,6 +640,7 @@ func (b *Builder) Add(destination string, extract bool, options AddAndCopyOption
 					StripSetuidBit: options.StripSetuidBit,
 					StripSetgidBit: options.StripSetgidBit,
 					StripStickyBit: options.StripStickyBit,
+					StripXattrs:    options.StripXattrs,
 				}
 				getErr = copier.Get(contextDir, contextDir, getOptions, []string{glob}, writer)
 				closeErr = writer.Close()
@@ -526,15 +665,17 @@ func (b *Builder) Add(destination string, extract bool, options AddAndCopyOption
 					_, putErr = io.Copy(hasher, pipeReader)
 				} else {
 					putOptions := copier.PutOptions{
-						UIDMap:          destUIDMap,
-						GIDMap:          destGIDMap,
-						DefaultDirOwner: chownDirs,
-						DefaultDirMode:  nil,
-						ChownDirs:       nil,
-						ChmodDirs:       nil,
-						ChownFiles:      nil,
-						ChmodFiles:      nil,
-						IgnoreDevices:   userns.RunningInUserNS(),
+						UIDMap:            destUIDMap,
+						GIDMap:            destGIDMap,
+						DefaultDirOwner:   chownDirs,
+						DefaultDirMode:    nil,
+						ChownDirs:         nil,
+						ChmodDirs:         nil,
+						ChownFiles:        nil,
+						ChmodFiles:        nil,
+						IgnoreDevices:     userns.RunningInUserNS(),
+						IgnoreXattrErrors: userns.RunningInUserNS(),
+						StripXattrs:       options.StripXattrs,
 					}
 					putErr = copier.Put(extractDirectory, extractDirectory, putOptions, io.TeeReader(pipeReader, hasher))
 				}
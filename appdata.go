@@ -0,0 +1,24 @@
+package buildah
+
+// GetAppData returns the value which was stored under key by a previous
+// call to SetAppData, or nil if no value is stored under that key.
+func (b *Builder) GetAppData(key string) []byte {
+	return b.AppData[key]
+}
+
+// SetAppData stores value under key, alongside the rest of the builder's
+// persisted state, so that a tool built on top of this library can recover
+// it later, including from a different process, via OpenBuilder.  Setting
+// value to nil removes whatever was previously stored under key.  As with
+// other Builder setters, the caller needs to call Save() for the change to
+// be written out.
+func (b *Builder) SetAppData(key string, value []byte) {
+	if value == nil {
+		delete(b.AppData, key)
+		return
+	}
+	if b.AppData == nil {
+		b.AppData = make(map[string][]byte)
+	}
+	b.AppData[key] = value
+}
@@ -178,6 +178,17 @@ type Builder struct {
 	ContentDigester CompositeDigester
 	// Devices are the additional devices to add to the containers
 	Devices define.ContainerDevices
+	// Snapshots is the set of names under which Snapshot() has archived
+	// the contents of the container's root filesystem for later use by
+	// Restore().  It should not be modified directly; use Snapshot() and
+	// Restore() instead.
+	Snapshots map[string]struct{} `json:"snapshots,omitempty"`
+	// AppData holds arbitrary metadata that tools built on top of this
+	// library can stash alongside the working container, keyed by name,
+	// so that it can be recovered later, including by a different
+	// process, via OpenBuilder.  It should not be modified directly; use
+	// SetAppData() and GetAppData() instead.
+	AppData map[string][]byte `json:"app-data,omitempty"`
 }
 
 // BuilderInfo are used as objects to display container information
@@ -283,6 +294,10 @@ type BuilderOptions struct {
 	// ReportWriter is an io.Writer which will be used to log the reading
 	// of the source image from a registry, if we end up pulling the image.
 	ReportWriter io.Writer
+	// Progress, if set, receives structured notifications of the progress
+	// of copying blobs while pulling the source image, in addition to
+	// whatever is written to ReportWriter.
+	Progress define.ProgressReporter
 	// github.com/containers/image/types SystemContext to hold credentials
 	// and other authentication/authorization information.
 	SystemContext *types.SystemContext
@@ -327,6 +342,11 @@ type BuilderOptions struct {
 	// OciDecryptConfig contains the config that can be used to decrypt an image if it is
 	// encrypted if non-nil. If nil, it does not attempt to decrypt an image.
 	OciDecryptConfig *encconfig.DecryptConfig
+	// RequirePrimarySource causes NewBuilder() to fail instead of silently
+	// falling back to a mirror or alternate search registry if FromImage
+	// couldn't be pulled from the primary (first-listed, or explicitly
+	// qualified) source.
+	RequirePrimarySource bool
 }
 
 // ImportOptions are used to initialize a Builder from an existing container
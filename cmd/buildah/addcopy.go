@@ -9,6 +9,7 @@ import (
 
 	"github.com/containers/buildah"
 	buildahcli "github.com/containers/buildah/pkg/cli"
+	"github.com/containers/buildah/pkg/events"
 	"github.com/containers/buildah/pkg/parse"
 	"github.com/containers/common/pkg/auth"
 	"github.com/containers/storage"
@@ -35,6 +36,7 @@ type addCopyResults struct {
 	creds            string
 	tlsVerify        bool
 	certDir          string
+	stripXattrs      bool
 }
 
 func createCommand(addCopy string, desc string, short string, opts *addCopyResults) *cobra.Command {
@@ -68,6 +70,7 @@ func applyFlagVars(flags *pflag.FlagSet, opts *addCopyResults) {
 	}
 	flags.StringVar(&opts.chown, "chown", "", "set the user and group ownership of the destination content")
 	flags.StringVar(&opts.chmod, "chmod", "", "set the access permissions of the destination content")
+	flags.BoolVar(&opts.stripXattrs, "strip-xattrs", false, "don't preserve extended attributes, which is also how ACLs and Linux security capabilities are recorded, on the copied content")
 	flags.StringVar(&opts.creds, "creds", "", "use `[username[:password]]` for accessing registries when pulling images")
 	if err := flags.MarkHidden("creds"); err != nil {
 		panic(fmt.Sprintf("error marking creds as hidden: %v", err))
@@ -223,11 +226,18 @@ func addAndCopyCmd(c *cobra.Command, args []string, verb string, iopts addCopyRe
 
 	builder.ContentDigester.Restart()
 
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrap(err, "error building system context")
+	}
+
 	options := buildah.AddAndCopyOptions{
 		Chmod:            iopts.chmod,
 		Chown:            iopts.chown,
 		ContextDir:       contextdir,
 		IDMappingOptions: idMappingOptions,
+		SystemContext:    systemContext,
+		StripXattrs:      iopts.stripXattrs,
 	}
 	if iopts.contextdir != "" {
 		var excludes []string
@@ -271,6 +281,11 @@ func addAndCopyCmd(c *cobra.Command, args []string, verb string, iopts addCopyRe
 		contentType = contentType + ":"
 	}
 	conditionallyAddHistory(builder, c, "/bin/sh -c #(nop) %s %s%s", verb, contentType, digest.Hex())
+
+	if err := events.Write(store, events.Event{Type: events.Copy, ID: builder.ContainerID, Name: builder.Container, Args: args}); err != nil {
+		logrus.Debugf("error recording %s event: %v", strings.ToLower(verb), err)
+	}
+
 	return builder.Save()
 }
 
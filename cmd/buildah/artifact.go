@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/containers/buildah"
+	"github.com/containers/buildah/define"
+	buildahcli "github.com/containers/buildah/pkg/cli"
+	"github.com/containers/buildah/pkg/formats"
+	"github.com/containers/buildah/pkg/parse"
+	"github.com/containers/common/libimage"
+	"github.com/containers/common/pkg/auth"
+	storageTransport "github.com/containers/image/v5/storage"
+	"github.com/containers/image/v5/transports/alltransports"
+	units "github.com/docker/go-units"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// artifactLabel marks an image built by "buildah artifact add" as holding
+// generic files rather than a runnable container image, so that "buildah
+// artifact ls" can find it again.  The vendored copy of the OCI image-spec
+// in this tree predates the "artifactType" manifest field from image-spec
+// 1.1, so there's no way to produce a manifest that a client speaking the
+// newer OCI Referrers API would recognize as an artifact; this label is
+// buildah's own bookkeeping, not part of the image it produces.
+const artifactLabel = "io.buildah.artifact"
+
+type artifactAddOptions struct {
+	authfile string
+	certDir  string
+	creds    string
+}
+
+type artifactPushOptions struct {
+	authfile         string
+	certDir          string
+	creds            string
+	removeSignatures bool
+	tlsVerify        bool
+}
+
+type artifactPullOptions struct {
+	authfile  string
+	certDir   string
+	creds     string
+	tlsVerify bool
+}
+
+type artifactRmOptions struct {
+	all   bool
+	force bool
+}
+
+type artifactLsParams struct {
+	Name string
+	ID   string
+	Size string
+}
+
+var artifactLsHeader = map[string]string{
+	"Name": "NAME",
+	"ID":   "ID",
+	"Size": "SIZE",
+}
+
+func init() {
+	artifactDescription := "\n  Manages generic OCI artifacts (arbitrary files, such as SBOMs, test\n  reports, or binaries) in local storage, using ordinary OCI images as the\n  storage and distribution mechanism."
+	artifactCommand := &cobra.Command{
+		Use:   "artifact",
+		Short: "Manage OCI artifacts",
+		Long:  artifactDescription,
+		Args:  cobra.NoArgs,
+		RunE:  func(cmd *cobra.Command, args []string) error { return cmd.Help() },
+	}
+	rootCmd.AddCommand(artifactCommand)
+
+	var addOpts artifactAddOptions
+	addCommand := &cobra.Command{
+		Use:   "add ARTIFACT FILE [FILE...]",
+		Short: "Package one or more files as an OCI artifact",
+		Long:  "\n  Packages one or more files into an OCI artifact and stores it locally\n  under the given name, for later use with \"buildah artifact push\".",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return artifactAddCmd(cmd, args, addOpts)
+		},
+		Example:           `buildah artifact add localhost/myartifact:latest report.sbom`,
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: completeImages,
+	}
+	addCommand.SetUsageTemplate(UsageTemplate())
+	addFlags := addCommand.Flags()
+	addFlags.StringVar(&addOpts.authfile, "authfile", auth.GetDefaultAuthFile(), "path of the authentication file. Use REGISTRY_AUTH_FILE environment variable to override")
+	addFlags.StringVar(&addOpts.certDir, "cert-dir", "", "use certificates at the specified path to access the registry")
+	addFlags.StringVar(&addOpts.creds, "creds", "", "use `[username[:password]]` for accessing the registry")
+	artifactCommand.AddCommand(addCommand)
+
+	var pushOpts artifactPushOptions
+	pushCommand := &cobra.Command{
+		Use:   "push ARTIFACT DESTINATION",
+		Short: "Push an OCI artifact to a registry",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return artifactPushCmd(cmd, args, pushOpts)
+		},
+		Example:           `buildah artifact push localhost/myartifact:latest docker://registry.example.com/myartifact:latest`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeImages,
+	}
+	pushCommand.SetUsageTemplate(UsageTemplate())
+	pushFlags := pushCommand.Flags()
+	pushFlags.StringVar(&pushOpts.authfile, "authfile", auth.GetDefaultAuthFile(), "path of the authentication file. Use REGISTRY_AUTH_FILE environment variable to override")
+	pushFlags.StringVar(&pushOpts.certDir, "cert-dir", "", "use certificates at the specified path to access the registry")
+	pushFlags.StringVar(&pushOpts.creds, "creds", "", "use `[username[:password]]` for accessing the registry")
+	pushFlags.BoolVar(&pushOpts.removeSignatures, "remove-signatures", false, "don't copy signatures when pushing the artifact")
+	pushFlags.BoolVar(&pushOpts.tlsVerify, "tls-verify", true, "require HTTPS and verify certificates when accessing the registry")
+	artifactCommand.AddCommand(pushCommand)
+
+	var pullOpts artifactPullOptions
+	pullCommand := &cobra.Command{
+		Use:   "pull ARTIFACT",
+		Short: "Pull an OCI artifact from a registry",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return artifactPullCmd(cmd, args, pullOpts)
+		},
+		Example: `buildah artifact pull registry.example.com/myartifact:latest`,
+		Args:    cobra.ExactArgs(1),
+	}
+	pullCommand.SetUsageTemplate(UsageTemplate())
+	pullFlags := pullCommand.Flags()
+	pullFlags.StringVar(&pullOpts.authfile, "authfile", auth.GetDefaultAuthFile(), "path of the authentication file. Use REGISTRY_AUTH_FILE environment variable to override")
+	pullFlags.StringVar(&pullOpts.certDir, "cert-dir", "", "use certificates at the specified path to access the registry")
+	pullFlags.StringVar(&pullOpts.creds, "creds", "", "use `[username[:password]]` for accessing the registry")
+	pullFlags.BoolVar(&pullOpts.tlsVerify, "tls-verify", true, "require HTTPS and verify certificates when accessing the registry")
+	artifactCommand.AddCommand(pullCommand)
+
+	lsCommand := &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List OCI artifacts in local storage",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return artifactLsCmd(cmd, args)
+		},
+		Args: cobra.NoArgs,
+	}
+	lsCommand.SetUsageTemplate(UsageTemplate())
+	artifactCommand.AddCommand(lsCommand)
+
+	var rmOpts artifactRmOptions
+	rmCommand := &cobra.Command{
+		Use:   "rm ARTIFACT [ARTIFACT...]",
+		Short: "Remove one or more OCI artifacts from local storage",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return artifactRmCmd(cmd, args, rmOpts)
+		},
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: completeImages,
+	}
+	rmCommand.SetUsageTemplate(UsageTemplate())
+	rmFlags := rmCommand.Flags()
+	rmFlags.BoolVarP(&rmOpts.all, "all", "a", false, "remove all artifacts")
+	rmFlags.BoolVarP(&rmOpts.force, "force", "f", false, "force removal of the artifact")
+	artifactCommand.AddCommand(rmCommand)
+}
+
+func artifactAddCmd(c *cobra.Command, args []string, iopts artifactAddOptions) error {
+	name := args[0]
+	files := args[1:]
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+
+	options := buildah.BuilderOptions{
+		FromImage:       "scratch",
+		SystemContext:   systemContext,
+		CommonBuildOpts: &define.CommonBuildOptions{},
+	}
+	builder, err := buildah.NewBuilder(getContext(), store, options)
+	if err != nil {
+		return errors.Wrapf(err, "error creating artifact container")
+	}
+
+	for _, file := range files {
+		if err := builder.Add(filepath.Base(file), false, buildah.AddAndCopyOptions{}, file); err != nil {
+			if delErr := builder.Delete(); delErr != nil {
+				logrus.Debugf("error cleaning up artifact container: %v", delErr)
+			}
+			return errors.Wrapf(err, "error adding %q to artifact", file)
+		}
+	}
+	builder.SetLabel(artifactLabel, "true")
+
+	dest, err := storageTransport.Transport.ParseStoreReference(store, name)
+	if err != nil {
+		if delErr := builder.Delete(); delErr != nil {
+			logrus.Debugf("error cleaning up artifact container: %v", delErr)
+		}
+		return errors.Wrapf(err, "error parsing artifact name %q", name)
+	}
+
+	id, _, _, err := builder.Commit(getContext(), dest, buildah.CommitOptions{SystemContext: systemContext})
+	if err != nil {
+		if delErr := builder.Delete(); delErr != nil {
+			logrus.Debugf("error cleaning up artifact container: %v", delErr)
+		}
+		return errors.Wrapf(err, "error committing artifact %q", name)
+	}
+	if err := builder.Delete(); err != nil {
+		return errors.Wrapf(err, "error cleaning up artifact container")
+	}
+
+	fmt.Printf("%s\n", id)
+	return nil
+}
+
+func artifactPushCmd(c *cobra.Command, args []string, iopts artifactPushOptions) error {
+	src, destSpec := args[0], args[1]
+
+	if err := auth.CheckAuthFile(iopts.authfile); err != nil {
+		return err
+	}
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+	dest, err := alltransports.ParseImageName(destSpec)
+	if err != nil {
+		return errors.Wrapf(err, "error parsing artifact destination %q", destSpec)
+	}
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+
+	options := buildah.PushOptions{
+		Store:            store,
+		SystemContext:    systemContext,
+		RemoveSignatures: iopts.removeSignatures,
+		ReportWriter:     nil,
+		MaxRetries:       maxPullPushRetries,
+		RetryDelay:       pullPushRetryDelay,
+	}
+	_, _, err = buildah.Push(getContext(), src, dest, options)
+	return errors.Wrapf(err, "error pushing artifact %q to %q", src, destSpec)
+}
+
+func artifactPullCmd(c *cobra.Command, args []string, iopts artifactPullOptions) error {
+	imageName := args[0]
+
+	if err := auth.CheckAuthFile(iopts.authfile); err != nil {
+		return err
+	}
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+
+	options := buildah.PullOptions{
+		Store:         store,
+		SystemContext: systemContext,
+		MaxRetries:    maxPullPushRetries,
+		RetryDelay:    pullPushRetryDelay,
+	}
+	id, err := buildah.Pull(getContext(), imageName, options)
+	if err != nil {
+		return errors.Wrapf(err, "error pulling artifact %q", imageName)
+	}
+	fmt.Printf("%s\n", id)
+	return nil
+}
+
+func artifactLsCmd(c *cobra.Command, args []string) error {
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+	runtime, err := libimage.RuntimeFromStore(store, &libimage.RuntimeOptions{SystemContext: systemContext})
+	if err != nil {
+		return err
+	}
+
+	images, err := runtime.ListImages(context.Background(), nil, &libimage.ListImagesOptions{
+		Filters: []string{"label=" + artifactLabel + "=true"},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error listing artifacts")
+	}
+
+	params := make([]artifactLsParams, 0, len(images))
+	for _, image := range images {
+		size, err := image.Size()
+		if err != nil {
+			logrus.Debugf("error determining size of artifact %s: %v", image.ID(), err)
+		}
+		name := image.ID()
+		if names := image.Names(); len(names) > 0 {
+			name = names[0]
+		}
+		params = append(params, artifactLsParams{
+			Name: name,
+			ID:   truncateID(image.ID(), true),
+			Size: units.HumanSizeWithPrecision(float64(size), 3),
+		})
+	}
+
+	out := formats.StdoutTemplateArray{Output: artifactLsToGeneric(params), Template: "table {{.Name}}\t{{.ID}}\t{{.Size}}", Fields: artifactLsHeader}
+	return formats.Writer(out).Out()
+}
+
+func artifactLsToGeneric(params []artifactLsParams) (genericParams []interface{}) {
+	for _, v := range params {
+		genericParams = append(genericParams, interface{}(v))
+	}
+	return genericParams
+}
+
+func artifactRmCmd(c *cobra.Command, args []string, iopts artifactRmOptions) error {
+	if len(args) == 0 && !iopts.all {
+		return errors.Errorf("artifact name or ID must be specified")
+	}
+	if len(args) > 0 && iopts.all {
+		return errors.Errorf("when using the --all switch, you may not pass any artifact names or IDs")
+	}
+
+	if err := buildahcli.VerifyFlagsArgsOrder(args); err != nil {
+		return err
+	}
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+	runtime, err := libimage.RuntimeFromStore(store, &libimage.RuntimeOptions{SystemContext: systemContext})
+	if err != nil {
+		return err
+	}
+
+	names := args
+	if iopts.all {
+		images, err := runtime.ListImages(context.Background(), nil, &libimage.ListImagesOptions{
+			Filters: []string{"label=" + artifactLabel + "=true"},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "error listing artifacts")
+		}
+		for _, image := range images {
+			names = append(names, image.ID())
+		}
+	}
+
+	options := &libimage.RemoveImagesOptions{Force: iopts.force}
+	rmiReports, rmiErrors := runtime.RemoveImages(context.Background(), names, options)
+	for _, r := range rmiReports {
+		if r.Removed {
+			fmt.Printf("%s\n", r.ID)
+		}
+	}
+
+	var multiE *multierror.Error
+	multiE = multierror.Append(multiE, rmiErrors...)
+	return multiE.ErrorOrNil()
+}
@@ -11,9 +11,11 @@ import (
 	"github.com/containers/buildah/define"
 	"github.com/containers/buildah/imagebuildah"
 	buildahcli "github.com/containers/buildah/pkg/cli"
+	"github.com/containers/buildah/pkg/completion"
 	"github.com/containers/buildah/pkg/parse"
 	"github.com/containers/buildah/util"
 	"github.com/containers/common/pkg/auth"
+	commonComp "github.com/containers/common/pkg/completion"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -82,6 +84,14 @@ func init() {
 	flags.AddFlagSet(&fromAndBudFlags)
 	flags.SetNormalizeFunc(buildahcli.AliasFlags)
 
+	flagCompletions := buildahcli.GetBudFlagsCompletions()
+	for name, comp := range buildahcli.GetFromAndBudFlagsCompletions() {
+		flagCompletions[name] = comp
+	}
+	flagCompletions["isolation"] = completion.AutocompleteIsolation
+	flagCompletions["format"] = completion.AutocompleteImageFormat
+	commonComp.CompleteCommandFlags(budCommand, flagCompletions)
+
 	rootCmd.AddCommand(budCommand)
 }
 
@@ -125,10 +135,27 @@ func budCmd(c *cobra.Command, inputArgs []string, iopts budOptions) error {
 
 	args := make(map[string]string)
 	if c.Flag("build-arg").Changed {
+		// providerCache avoids re-running the same "exec://" command, or
+		// re-reading the same "file://" path, if it's used to supply more
+		// than one build-arg.
+		providerCache := make(map[string]string)
 		for _, arg := range iopts.BuildArg {
 			av := strings.SplitN(arg, "=", 2)
 			if len(av) > 1 {
-				args[av[0]] = av[1]
+				val := av[1]
+				if parse.IsBuildArgProvider(val) {
+					resolved, ok := providerCache[val]
+					if !ok {
+						var err error
+						resolved, err = parse.ResolveBuildArgProvider(val)
+						if err != nil {
+							return errors.Wrapf(err, "error resolving build-arg %q", av[0])
+						}
+						providerCache[val] = resolved
+					}
+					val = resolved
+				}
+				args[av[0]] = val
 			} else {
 				// check if the env is set in the local environment and use that value if it is
 				if val, present := os.LookupEnv(av[0]); present {
@@ -140,6 +167,22 @@ func budCmd(c *cobra.Command, inputArgs []string, iopts budOptions) error {
 		}
 	}
 
+	if iopts.ContainerfileContent != "" {
+		if len(iopts.File) > 0 {
+			return errors.Errorf("cannot use --containerfile-content with --file")
+		}
+		containerfile, err := ioutil.TempFile(parse.GetTempDir(), "buildah-containerfile-content")
+		if err != nil {
+			return errors.Wrapf(err, "error creating temporary file for --containerfile-content")
+		}
+		defer os.Remove(containerfile.Name())
+		defer containerfile.Close()
+		if _, err := containerfile.WriteString(iopts.ContainerfileContent); err != nil {
+			return errors.Wrapf(err, "error writing --containerfile-content to temporary file")
+		}
+		iopts.File = []string{containerfile.Name()}
+	}
+
 	dockerfiles := getDockerfiles(iopts.File)
 	format, err := getFormat(iopts.Format)
 	if err != nil {
@@ -234,6 +277,10 @@ func budCmd(c *cobra.Command, inputArgs []string, iopts budOptions) error {
 		return errors.Wrapf(err, "error building system context")
 	}
 
+	if err := maybePruneForStorageGC(getContext(), store, systemContext, iopts.StorageGCThreshold); err != nil {
+		return err
+	}
+
 	isolation, err := parse.IsolationOption(iopts.Isolation)
 	if err != nil {
 		return err
@@ -249,25 +296,18 @@ func budCmd(c *cobra.Command, inputArgs []string, iopts budOptions) error {
 		return err
 	}
 
-	pullFlagsCount := 0
-	if c.Flag("pull").Changed {
-		pullFlagsCount++
-	}
-	if c.Flag("pull-always").Changed {
-		pullFlagsCount++
-	}
-	if c.Flag("pull-never").Changed {
-		pullFlagsCount++
-	}
-
-	if pullFlagsCount > 1 {
-		return errors.Errorf("can only set one of 'pull' or 'pull-always' or 'pull-never'")
+	if err := buildahcli.ValidatePullFlags(c.Flags()); err != nil {
+		return err
 	}
 
 	if c.Flag("layers").Changed && c.Flag("no-cache").Changed {
 		return errors.Errorf("can only set one of 'layers' or 'no-cache'")
 	}
 
+	if iopts.Watch && iopts.Scan != "" {
+		return errors.Errorf("--watch cannot be combined with --scan: rebuilds triggered by --watch are not scanned")
+	}
+
 	if (c.Flag("rm").Changed || c.Flag("force-rm").Changed) && (!c.Flag("layers").Changed && !c.Flag("no-cache").Changed) {
 		return errors.Errorf("'rm' and 'force-rm' can only be set with either 'layers' or 'no-cache'")
 	}
@@ -309,6 +349,16 @@ func budCmd(c *cobra.Command, inputArgs []string, iopts budOptions) error {
 		return errors.Wrapf(err, "unable to obtain decrypt config")
 	}
 
+	maxBaseAge, baseAgePolicy, err := parseBaseAgePolicy(iopts.MaxBaseAge, iopts.BaseAgePolicy)
+	if err != nil {
+		return err
+	}
+
+	stageEnv, err := parseStageEnv(iopts.StageEnv)
+	if err != nil {
+		return err
+	}
+
 	options := define.BuildOptions{
 		AddCapabilities:         iopts.CapAdd,
 		AdditionalTags:          tags,
@@ -334,6 +384,7 @@ func budCmd(c *cobra.Command, inputArgs []string, iopts budOptions) error {
 		Isolation:               isolation,
 		Labels:                  iopts.Label,
 		Layers:                  layers,
+		CacheBackend:            iopts.CacheBackend,
 		LogRusage:               iopts.LogRusage,
 		Manifest:                iopts.Manifest,
 		MaxPullPushRetries:      maxPullPushRetries,
@@ -347,6 +398,10 @@ func budCmd(c *cobra.Command, inputArgs []string, iopts budOptions) error {
 		PullPushRetryDelay:      pullPushRetryDelay,
 		Quiet:                   iopts.Quiet,
 		RemoveIntermediateCtrs:  iopts.Rm,
+		RequirePrimarySource:    iopts.RequirePrimarySource,
+		MaxBaseAge:              maxBaseAge,
+		BaseAgePolicy:           baseAgePolicy,
+		StageEnv:                stageEnv,
 		ReportWriter:            reporter,
 		Runtime:                 iopts.Runtime,
 		RuntimeArgs:             runtimeFlags,
@@ -376,8 +431,74 @@ func budCmd(c *cobra.Command, inputArgs []string, iopts budOptions) error {
 		options.ReportWriter = ioutil.Discard
 	}
 
-	_, _, err = imagebuildah.BuildDockerfiles(getContext(), store, options, dockerfiles...)
-	return err
+	if iopts.Watch {
+		return watchAndBuild(getContext(), store, options, dockerfiles, contextDir)
+	}
+
+	imageID, _, err := imagebuildah.BuildDockerfiles(getContext(), store, options, dockerfiles...)
+	if err != nil {
+		return err
+	}
+
+	if iopts.Scan != "" {
+		result, err := runScanner(getContext(), imageID, iopts.Scan, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := recordScanArtifact(getContext(), store, systemContext, imageID, result); err != nil {
+			logrus.Errorf("error recording scan results for %q as an artifact: %v", imageID, err)
+		}
+		if result.ExceedsSeverity(iopts.ScanFailOn) {
+			return errors.Errorf("built image %q has a vulnerability at or above severity %q", imageID, iopts.ScanFailOn)
+		}
+	}
+
+	return nil
+}
+
+// parseBaseAgePolicy parses the --max-base-age and --base-age-policy values
+// into the duration and policy that define.BuildOptions expects.
+func parseBaseAgePolicy(maxBaseAge, baseAgePolicy string) (time.Duration, define.BaseAgePolicy, error) {
+	if maxBaseAge == "" {
+		return 0, define.BaseAgeWarn, nil
+	}
+	age, err := time.ParseDuration(maxBaseAge)
+	if err != nil {
+		return 0, define.BaseAgeWarn, errors.Wrapf(err, "error parsing --max-base-age value %q", maxBaseAge)
+	}
+	policy, ok := define.BaseAgePolicyMap[baseAgePolicy]
+	if !ok {
+		return 0, define.BaseAgeWarn, errors.Errorf("unrecognized --base-age-policy value %q: valid values are \"warn\" or \"fail\"", baseAgePolicy)
+	}
+	return age, policy, nil
+}
+
+// parseStageEnv parses --stage-env values in "stage:name=value" format into
+// a map of stage name (or numeric position) to the environment variables
+// that should be set for that stage alone.
+func parseStageEnv(stageEnv []string) (map[string]map[string]string, error) {
+	if len(stageEnv) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[string]map[string]string)
+	for _, spec := range stageEnv {
+		stage, assignment := "", spec
+		if i := strings.Index(spec, ":"); i != -1 {
+			stage, assignment = spec[:i], spec[i+1:]
+		}
+		if stage == "" || assignment == "" {
+			return nil, errors.Errorf("invalid --stage-env value %q: expected \"stage:name=value\"", spec)
+		}
+		av := strings.SplitN(assignment, "=", 2)
+		if len(av) != 2 {
+			return nil, errors.Errorf("invalid --stage-env value %q: expected \"stage:name=value\"", spec)
+		}
+		if parsed[stage] == nil {
+			parsed[stage] = make(map[string]string)
+		}
+		parsed[stage][av[0]] = av[1]
+	}
+	return parsed, nil
 }
 
 // discoverContainerfile tries to find a Containerfile or a Dockerfile within the provided `path`.
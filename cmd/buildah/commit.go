@@ -8,6 +8,8 @@ import (
 	"github.com/containers/buildah"
 	"github.com/containers/buildah/define"
 	buildahcli "github.com/containers/buildah/pkg/cli"
+	"github.com/containers/buildah/pkg/completion"
+	"github.com/containers/buildah/pkg/events"
 	"github.com/containers/buildah/pkg/parse"
 	"github.com/containers/buildah/util"
 	"github.com/containers/common/pkg/auth"
@@ -38,8 +40,11 @@ type commitInputOptions struct {
 	signBy             string
 	squash             bool
 	tlsVerify          bool
+	validate           bool
 	encryptionKeys     []string
 	encryptLayers      []int
+	scan               string
+	scanFailOn         string
 }
 
 func init() {
@@ -57,6 +62,7 @@ func init() {
 		Example: `buildah commit containerID
   buildah commit containerID newImageName
   buildah commit containerID docker://localhost:5000/imageId`,
+		ValidArgsFunction: completeContainers,
 	}
 	commitCommand.SetUsageTemplate(UsageTemplate())
 	flags := commitCommand.Flags()
@@ -82,6 +88,9 @@ func init() {
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "don't output progress information when writing images")
 	flags.StringVar(&opts.referenceTime, "reference-time", "", "set the timestamp on the image to match the named `file`")
 	flags.StringVar(&opts.signBy, "sign-by", "", "sign the image using a GPG key with the specified `FINGERPRINT`")
+	flags.StringVar(&opts.scan, "scan", "", "scan the committed image for vulnerabilities using the given scanner `command` and record the results as an artifact")
+	flags.StringVar(&opts.scanFailOn, "scan-fail-on", "", "with --scan, fail the commit if a vulnerability at or above this `severity` is found")
+	flags.BoolVar(&opts.validate, "validate", false, "check that the configured USER exists and warn about a missing WORKDIR or ENTRYPOINT/CMD before committing")
 
 	if err := flags.MarkHidden("omit-timestamp"); err != nil {
 		panic(fmt.Sprintf("error marking omit-timestamp as hidden: %v", err))
@@ -100,6 +109,10 @@ func init() {
 	flags.BoolVar(&opts.squash, "squash", false, "produce an image with only one layer")
 	flags.BoolVar(&opts.tlsVerify, "tls-verify", true, "Require HTTPS and verify certificates when accessing the registry. TLS verification cannot be used when talking to an insecure registry.")
 
+	if err := commitCommand.RegisterFlagCompletionFunc("format", completion.AutocompleteImageFormat); err != nil {
+		logrus.Errorf("error registering completion function for --format flag: %v", err)
+	}
+
 	rootCmd.AddCommand(commitCommand)
 
 }
@@ -190,6 +203,7 @@ func commitCmd(c *cobra.Command, args []string, iopts commitInputOptions) error
 		SignBy:                iopts.signBy,
 		OciEncryptConfig:      encConfig,
 		OciEncryptLayers:      encLayers,
+		Validate:              iopts.validate,
 	}
 	exclusiveFlags := 0
 	if c.Flag("reference-time").Changed {
@@ -237,6 +251,23 @@ func commitCmd(c *cobra.Command, args []string, iopts commitInputOptions) error
 		fmt.Printf("%s\n", id)
 	}
 
+	if err := events.Write(store, events.Event{Type: events.Commit, ID: id, Name: builder.Container, Args: []string{image}}); err != nil {
+		logrus.Debugf("error recording commit event: %v", err)
+	}
+
+	if iopts.scan != "" {
+		result, err := runScanner(ctx, id, iopts.scan, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := recordScanArtifact(ctx, store, systemContext, id, result); err != nil {
+			logrus.Errorf("error recording scan results for %q as an artifact: %v", id, err)
+		}
+		if result.ExceedsSeverity(iopts.scanFailOn) {
+			return errors.Errorf("committed image %q has a vulnerability at or above severity %q", id, iopts.scanFailOn)
+		}
+	}
+
 	if iopts.rm {
 		return builder.Delete()
 	}
@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"os"
 	"time"
 
 	"github.com/containers/buildah"
 	"github.com/containers/buildah/define"
+	"github.com/containers/buildah/pkg/jsonschema"
 	"github.com/containers/common/pkg/umask"
 	"github.com/containers/image/v5/image"
 	"github.com/containers/image/v5/manifest"
@@ -276,6 +279,23 @@ func Tail(a []string) []string {
 	return []string{}
 }
 
+// printJSONSchema prints the JSON Schema describing the shape of v (a
+// pointer to, or an instance of, the struct used for a command's --json or
+// --format json output) and exits successfully, for commands that accept a
+// --schema flag.
+func printJSONSchema(v interface{}, id string) error {
+	doc, err := jsonschema.ForType(v, id)
+	if err != nil {
+		return errors.Wrapf(err, "error generating JSON Schema for %s", id)
+	}
+	data, err := json.MarshalIndent(doc, "", "    ")
+	if err != nil {
+		return errors.Wrapf(err, "error formatting JSON Schema for %s", id)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
 // UsageTemplate returns the usage template for podman commands
 // This blocks the displaying of the global options. The main podman
 // command should not use this.
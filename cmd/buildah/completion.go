@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// completeContainers implements shell completion for arguments that name a
+// working container, by asking the store for the containers it knows about.
+func completeContainers(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := getStore(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	builders, err := openBuilders(store)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []string
+	for _, builder := range builders {
+		if strings.HasPrefix(builder.Container, toComplete) {
+			completions = append(completions, builder.Container)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeImages implements shell completion for arguments that name a
+// local image, by asking the store for the images it knows about.
+func completeImages(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	store, err := getStore(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	images, err := store.Images()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []string
+	for _, image := range images {
+		for _, name := range image.Names {
+			if strings.HasPrefix(name, toComplete) {
+				completions = append(completions, name)
+			}
+		}
+		if strings.HasPrefix(image.ID, toComplete) {
+			completions = append(completions, image.ID)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeContainersAndImages implements shell completion for arguments
+// that can name either a working container or a local image.
+func completeContainersAndImages(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	containers, _ := completeContainers(cmd, args, toComplete)
+	images, _ := completeImages(cmd, args, toComplete)
+	return append(containers, images...), cobra.ShellCompDirectiveNoFileComp
+}
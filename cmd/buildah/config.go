@@ -39,6 +39,8 @@ type configResults struct {
 	label                  []string
 	onbuild                []string
 	os                     string
+	osVersion              string
+	osFeature              []string
 	ports                  []string
 	shell                  string
 	stopSignal             string
@@ -87,6 +89,8 @@ func init() {
 	flags.StringArrayVarP(&opts.label, "label", "l", []string{}, "add image configuration `label` e.g. label=value")
 	flags.StringSliceVar(&opts.onbuild, "onbuild", []string{}, "add onbuild command to be run on images based on this image. Only supported on 'docker' formatted images")
 	flags.StringVar(&opts.os, "os", "", "set `operating system` of the target image")
+	flags.StringVar(&opts.osVersion, "os-version", "", "set operating system `version` of the target image")
+	flags.StringArrayVar(&opts.osFeature, "os-feature", []string{}, "add operating system `feature` required by the target image, `feature-` to remove it, or \"-\" to clear the list (default [])")
 	flags.StringSliceVarP(&opts.ports, "port", "p", []string{}, "add `port` to expose when running containers based on image (default [])")
 	flags.StringVar(&opts.shell, "shell", "", "add `shell` to run in containers")
 	flags.StringVar(&opts.stopSignal, "stop-signal", "", "set `stop signal` for containers based on image")
@@ -172,6 +176,21 @@ func updateConfig(builder *buildah.Builder, c *cobra.Command, iopts configResult
 	if c.Flag("os").Changed {
 		builder.SetOS(iopts.os)
 	}
+	if c.Flag("os-version").Changed {
+		builder.SetOSVersion(iopts.osVersion)
+	}
+	if c.Flag("os-feature").Changed {
+		for _, feature := range iopts.osFeature {
+			switch {
+			case feature == "-":
+				builder.ClearOSFeatures()
+			case strings.HasSuffix(feature, "-"):
+				builder.UnsetOSFeature(strings.TrimSuffix(feature, "-"))
+			default:
+				builder.SetOSFeature(feature)
+			}
+		}
+	}
 	if c.Flag("user").Changed {
 		builder.SetUser(iopts.user)
 		conditionallyAddHistory(builder, c, "/bin/sh -c #(nop) USER %s", iopts.user)
@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strings"
 	"text/template"
+	"time"
 
 	"github.com/containers/buildah"
 	"github.com/containers/buildah/define"
@@ -48,12 +49,20 @@ type containerOptions struct {
 	noHeading  bool
 	noTruncate bool
 	quiet      bool
+	columns    string
 }
 
+// defaultContainerColumns are the columns used with --format csv/tsv if
+// --columns wasn't also given.
+var defaultContainerColumns = []string{"ContainerID", "ContainerName", "ImageID", "ImageName"}
+
 type containerFilterParams struct {
 	id       string
 	name     string
 	ancestor string
+	before   time.Time
+	since    time.Time
+	label    string
 }
 
 type containersResults struct {
@@ -64,6 +73,8 @@ type containersResults struct {
 	noheading  bool
 	notruncate bool
 	quiet      bool
+	schema     bool
+	columns    string
 }
 
 func init() {
@@ -94,11 +105,16 @@ func init() {
 	flags.BoolVarP(&opts.noheading, "noheading", "n", false, "do not print column headings")
 	flags.BoolVar(&opts.notruncate, "notruncate", false, "do not truncate output")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "display only container IDs")
+	flags.BoolVar(&opts.schema, "schema", false, "print the JSON Schema for --json output and exit")
+	flags.StringVar(&opts.columns, "columns", "", fmt.Sprintf("comma-separated list of columns to include with --format csv/tsv (default %q)", strings.Join(defaultContainerColumns, ",")))
 
 	rootCmd.AddCommand(containersCommand)
 }
 
 func containersCmd(c *cobra.Command, args []string, iopts containersResults) error {
+	if iopts.schema {
+		return printJSONSchema(jsonContainer{}, "buildah-containers")
+	}
 	if len(args) > 0 {
 		return errors.New("'buildah containers' does not accept arguments")
 	}
@@ -118,11 +134,12 @@ func containersCmd(c *cobra.Command, args []string, iopts containersResults) err
 		noHeading:  iopts.noheading,
 		noTruncate: iopts.notruncate,
 		quiet:      iopts.quiet,
+		columns:    iopts.columns,
 	}
 
 	var params *containerFilterParams
 	if c.Flag("filter").Changed {
-		params, err = parseCtrFilter(iopts.filter)
+		params, err = parseCtrFilter(store, iopts.filter)
 		if err != nil {
 			return errors.Wrapf(err, "error parsing filter")
 		}
@@ -164,7 +181,8 @@ func outputContainers(store storage.Store, opts containerOptions, params *contai
 		// only output containers created by buildah
 		for _, builder := range builders {
 			image := imageNameForID(builder.FromImageID)
-			if !matchesCtrFilter(builder.ContainerID, builder.Container, builder.FromImageID, image, params) {
+			created := containerCreatedTime(store, builder.ContainerID)
+			if !matchesCtrFilter(builder.ContainerID, builder.Container, builder.FromImageID, image, created, builder.Labels(), params) {
 				continue
 			}
 			if opts.json {
@@ -186,9 +204,9 @@ func outputContainers(store storage.Store, opts containerOptions, params *contai
 		}
 	} else {
 		// output all containers currently in storage
-		builderMap := make(map[string]struct{})
+		builderMap := make(map[string]*buildah.Builder)
 		for _, builder := range builders {
-			builderMap[builder.ContainerID] = struct{}{}
+			builderMap[builder.ContainerID] = builder
 		}
 		containers, err2 := store.Containers()
 		if err2 != nil {
@@ -199,12 +217,14 @@ func outputContainers(store storage.Store, opts containerOptions, params *contai
 			if len(container.Names) > 0 {
 				name = container.Names[0]
 			}
-			_, ours := builderMap[container.ID]
+			ourBuilder, ours := builderMap[container.ID]
 			builder := ""
+			var labels map[string]string
 			if ours {
 				builder = "   *"
+				labels = ourBuilder.Labels()
 			}
-			if !matchesCtrFilter(container.ID, name, container.ImageID, imageNameForID(container.ImageID), params) {
+			if !matchesCtrFilter(container.ID, name, container.ImageID, imageNameForID(container.ImageID), container.Created, labels, params) {
 				continue
 			}
 			if opts.json {
@@ -234,6 +254,19 @@ func outputContainers(store storage.Store, opts containerOptions, params *contai
 		return nil
 	}
 
+	if opts.format == "csv" || opts.format == "tsv" {
+		columns := defaultContainerColumns
+		if opts.columns != "" {
+			columns = strings.Split(opts.columns, ",")
+		}
+		delimiter := ','
+		if opts.format == "tsv" {
+			delimiter = '\t'
+		}
+		out := formats.DSVStructArray{Output: containersToGeneric(containerOutput), Columns: columns, Headers: containersHeader, Delimiter: delimiter}
+		return formats.Writer(out).Out()
+	}
+
 	if opts.format != "" {
 		out := formats.StdoutTemplateArray{Output: containersToGeneric(containerOutput), Template: opts.format, Fields: containersHeader}
 		return formats.Writer(out).Out()
@@ -294,7 +327,17 @@ func containerOutputHeader(truncate bool) {
 	}
 }
 
-func parseCtrFilter(filter string) (*containerFilterParams, error) {
+// containerCreatedTime returns the time at which the container named or
+// identified by id was created, or the zero time if it can't be looked up.
+func containerCreatedTime(store storage.Store, id string) time.Time {
+	container, err := store.Container(id)
+	if err != nil {
+		return time.Time{}
+	}
+	return container.Created
+}
+
+func parseCtrFilter(store storage.Store, filter string) (*containerFilterParams, error) {
 	params := new(containerFilterParams)
 	filters := strings.Split(filter, ",")
 	for _, param := range filters {
@@ -309,6 +352,20 @@ func parseCtrFilter(filter string) (*containerFilterParams, error) {
 			params.name = pair[1]
 		case "ancestor":
 			params.ancestor = pair[1]
+		case "label":
+			params.label = pair[1]
+		case "before":
+			created := containerCreatedTime(store, pair[1])
+			if created.IsZero() {
+				return nil, errors.Errorf("no such container %q for \"before\" filter", pair[1])
+			}
+			params.before = created
+		case "since":
+			created := containerCreatedTime(store, pair[1])
+			if created.IsZero() {
+				return nil, errors.Errorf("no such container %q for \"since\" filter", pair[1])
+			}
+			params.since = created
 		default:
 			return nil, errors.Errorf("invalid filter %q", pair[0])
 		}
@@ -327,7 +384,19 @@ func matchesAncestor(imgName, imgID, argName string) bool {
 	return matchesReference(imgName, argName)
 }
 
-func matchesCtrFilter(ctrID, ctrName, imgID, imgName string, params *containerFilterParams) bool {
+func matchesLabel(labels map[string]string, argLabel string) bool {
+	key, value := argLabel, ""
+	if pair := strings.SplitN(argLabel, "=", 2); len(pair) == 2 {
+		key, value = pair[0], pair[1]
+	}
+	v, ok := labels[key]
+	if !ok {
+		return false
+	}
+	return value == "" || v == value
+}
+
+func matchesCtrFilter(ctrID, ctrName, imgID, imgName string, created time.Time, labels map[string]string, params *containerFilterParams) bool {
 	if params == nil {
 		return true
 	}
@@ -340,5 +409,14 @@ func matchesCtrFilter(ctrID, ctrName, imgID, imgName string, params *containerFi
 	if params.ancestor != "" && !matchesAncestor(imgName, imgID, params.ancestor) {
 		return false
 	}
+	if !params.before.IsZero() && !created.Before(params.before) {
+		return false
+	}
+	if !params.since.IsZero() && !created.After(params.since) {
+		return false
+	}
+	if params.label != "" && !matchesLabel(labels, params.label) {
+		return false
+	}
 	return true
 }
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/containers/buildah"
+	"github.com/containers/buildah/copier"
+	"github.com/containers/storage"
+	"github.com/containers/storage/pkg/idtools"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type cpOptions struct {
+	archive    bool
+	followLink bool
+}
+
+func init() {
+	var (
+		opts          cpOptions
+		cpDescription = "\n  Copies content between a working container's filesystem and the local\n  filesystem.  Either SRC or DEST, but not both, may be prefixed with a\n  container's name or ID followed by a colon, to name a path inside of\n  that container's filesystem instead of on the local filesystem."
+	)
+	cpCommand := &cobra.Command{
+		Use:   "cp [options] SRC DEST",
+		Short: "Copy content into or out of a container's filesystem",
+		Long:  cpDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cpCmd(cmd, args, opts)
+		},
+		Example: `buildah cp containerID:/home/myuser/myproject.tar /home/myuser/
+  buildah cp /home/myuser/myproject.tar containerID:/root/
+  buildah cp -a containerID:/etc/hosts /home/myuser/hosts`,
+		Args:              cobra.ExactArgs(2),
+		ValidArgsFunction: completeContainers,
+	}
+	cpCommand.SetUsageTemplate(UsageTemplate())
+	flags := cpCommand.Flags()
+	flags.BoolVarP(&opts.archive, "archive", "a", false, "preserve the source's numeric ownership when copying, instead of normalizing it")
+	flags.BoolVarP(&opts.followLink, "follow-link", "L", false, "follow the symbolic link named by SRC's path when copying out of a container")
+
+	rootCmd.AddCommand(cpCommand)
+}
+
+// cpEndpoint is one side of a "buildah cp" invocation: either a path on the
+// local filesystem, or a path inside of a working container's root
+// filesystem.
+type cpEndpoint struct {
+	builder *buildah.Builder
+	path    string
+}
+
+// resolveCpEndpoint interprets "name:path" as a reference to "path" inside
+// of the working container named "name", and anything else as a path on the
+// local filesystem.
+func resolveCpEndpoint(store storage.Store, arg string) cpEndpoint {
+	if i := strings.Index(arg, ":"); i > 0 {
+		name, path := arg[:i], arg[i+1:]
+		if builder, err := openBuilder(getContext(), store, name); err == nil {
+			return cpEndpoint{builder: builder, path: path}
+		}
+	}
+	return cpEndpoint{path: arg}
+}
+
+func cpCmd(c *cobra.Command, args []string, iopts cpOptions) error {
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+
+	src := resolveCpEndpoint(store, args[0])
+	dest := resolveCpEndpoint(store, args[1])
+
+	switch {
+	case src.builder != nil && dest.builder != nil:
+		return errors.New("copying directly between two containers is not supported: copy through the local filesystem instead")
+	case src.builder == nil && dest.builder == nil:
+		return errors.Errorf("neither %q nor %q names a container: prefix SRC or DEST with a container's name or ID followed by \":\"", args[0], args[1])
+	case dest.builder != nil:
+		return copyIntoContainer(dest.builder, src.path, dest.path, iopts)
+	default:
+		return copyOutOfContainer(src.builder, src.path, dest.path, iopts)
+	}
+}
+
+// copyIntoContainer implements "buildah cp SRC CTR:DEST" using the same
+// Add() tar pipeline that "buildah add" and "buildah copy" use.
+func copyIntoContainer(builder *buildah.Builder, src, dest string, iopts cpOptions) error {
+	options := buildah.AddAndCopyOptions{
+		PreserveOwnership: iopts.archive,
+	}
+	if err := builder.Add(dest, false, options, src); err != nil {
+		return errors.Wrapf(err, "error copying %q into container %q", src, builder.Container)
+	}
+	return builder.Save()
+}
+
+// copyOutOfContainer implements "buildah cp CTR:SRC DEST" by mounting the
+// container and running the copier package's Get/Put pipeline, the same one
+// that "buildah add"/"buildah copy" use to move content the other way, in
+// reverse.
+func copyOutOfContainer(builder *buildah.Builder, src, dest string, iopts cpOptions) error {
+	mountPoint, err := builder.Mount(builder.MountLabel)
+	if err != nil {
+		return errors.Wrapf(err, "error mounting container %q", builder.Container)
+	}
+	defer func() {
+		if err := builder.Unmount(); err != nil {
+			logrus.Errorf("error unmounting container %q: %v", builder.Container, err)
+		}
+	}()
+
+	if !filepath.IsAbs(src) {
+		src = filepath.Join(string(os.PathSeparator), builder.WorkDir(), src)
+	}
+	sourcePath := filepath.Join(mountPoint, src)
+
+	srcStats, err := copier.Stat(mountPoint, mountPoint, copier.StatOptions{}, []string{sourcePath})
+	if err != nil {
+		return errors.Wrapf(err, "error checking on %q in container %q", src, builder.Container)
+	}
+	if len(srcStats) != 1 || srcStats[0].Error != "" || len(srcStats[0].Globbed) == 0 {
+		return errors.Errorf("no such file or directory: %q in container %q", src, builder.Container)
+	}
+	if len(srcStats[0].Globbed) > 1 {
+		return errors.Errorf("%q matched more than one item in container %q", src, builder.Container)
+	}
+	sourceItem := srcStats[0].Globbed[0]
+	sourceIsDir := srcStats[0].Results[sourceItem].IsDir
+
+	// Figure out where on the host the content should land: inside an
+	// existing directory named by dest, or under the name dest itself.
+	destDir := dest
+	rename := map[string]string{}
+	if destInfo, err := os.Stat(dest); err == nil && destInfo.IsDir() {
+		// Copying into an existing host directory: keep the source's own name.
+	} else if !sourceIsDir {
+		destDir = filepath.Dir(dest)
+		rename[filepath.Base(sourceItem)] = filepath.Base(dest)
+	} else {
+		if err := os.MkdirAll(dest, 0755); err != nil {
+			return errors.Wrapf(err, "error creating destination directory %q", dest)
+		}
+	}
+
+	getOptions := copier.GetOptions{
+		NoDerefSymlinks: !iopts.followLink,
+		Rename:          rename,
+	}
+	putOptions := copier.PutOptions{}
+	if !iopts.archive {
+		owner := &idtools.IDPair{UID: os.Getuid(), GID: os.Getgid()}
+		putOptions.ChownDirs = owner
+		putOptions.ChownFiles = owner
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	getErrChan := make(chan error, 1)
+	go func() {
+		err := copier.Get(mountPoint, mountPoint, getOptions, []string{sourcePath}, pipeWriter)
+		pipeWriter.CloseWithError(err)
+		getErrChan <- err
+	}()
+	putErr := copier.Put(destDir, destDir, putOptions, pipeReader)
+	pipeReader.Close()
+	getErr := <-getErrChan
+
+	if getErr != nil {
+		return errors.Wrapf(getErr, "error reading %q from container %q", src, builder.Container)
+	}
+	if putErr != nil {
+		return errors.Wrapf(putErr, "error writing %q", dest)
+	}
+	return nil
+}
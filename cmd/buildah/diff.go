@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/containers/buildah"
+	buildahcli "github.com/containers/buildah/pkg/cli"
+	"github.com/containers/buildah/pkg/parse"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type diffOptions struct {
+	format string
+}
+
+type jsonDiffEntry struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"`
+	Size int64  `json:"size"`
+}
+
+func init() {
+	var (
+		opts            diffOptions
+		diffDescription = "\n  Displays the paths that were added, changed, or removed either between a\n  working container and its base image, or between two images."
+	)
+	diffCommand := &cobra.Command{
+		Use:   "diff",
+		Short: "Inspect changes to a container's or image's filesystem",
+		Long:  diffDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return diffCmd(cmd, args, opts)
+		},
+		Example: `buildah diff containerID
+  buildah diff baseImage newImage
+  buildah diff --format json containerID`,
+		Args: cobra.RangeArgs(1, 2),
+	}
+	diffCommand.SetUsageTemplate(UsageTemplate())
+
+	flags := diffCommand.Flags()
+	flags.StringVar(&opts.format, "format", "", `alternate format for the output, one of "" or "json"`)
+
+	rootCmd.AddCommand(diffCommand)
+}
+
+func diffCmd(c *cobra.Command, args []string, iopts diffOptions) error {
+	if err := buildahcli.VerifyFlagsArgsOrder(args); err != nil {
+		return err
+	}
+	if iopts.format != "" && iopts.format != "json" {
+		return errors.Errorf("unrecognized --format value %q: only \"json\" is supported", iopts.format)
+	}
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+	ctx := getContext()
+
+	var from, to string
+	switch len(args) {
+	case 1:
+		builder, err := openBuilder(ctx, store, args[0])
+		if err != nil {
+			return errors.Wrapf(err, "error reading build container %q", args[0])
+		}
+		container, err := store.Container(builder.ContainerID)
+		if err != nil {
+			return errors.Wrapf(err, "error reading container %q", args[0])
+		}
+		baseImage, err := store.Image(builder.FromImageID)
+		if err != nil {
+			return errors.Wrapf(err, "error reading base image for container %q", args[0])
+		}
+		from, to = baseImage.TopLayer, container.LayerID
+	case 2:
+		fromBuilder, err := openImage(ctx, systemContext, store, args[0])
+		if err != nil {
+			return errors.Wrapf(err, "error reading image %q", args[0])
+		}
+		toBuilder, err := openImage(ctx, systemContext, store, args[1])
+		if err != nil {
+			return errors.Wrapf(err, "error reading image %q", args[1])
+		}
+		fromImage, err := store.Image(fromBuilder.FromImageID)
+		if err != nil {
+			return errors.Wrapf(err, "error reading image %q", args[0])
+		}
+		toImage, err := store.Image(toBuilder.FromImageID)
+		if err != nil {
+			return errors.Wrapf(err, "error reading image %q", args[1])
+		}
+		from, to = fromImage.TopLayer, toImage.TopLayer
+	}
+
+	entries, err := buildah.GetLayerDiff(store, from, to)
+	if err != nil {
+		return errors.Wrapf(err, "error computing diff")
+	}
+
+	if iopts.format == "json" {
+		return formatDiffJSON(entries)
+	}
+	for _, entry := range entries {
+		fmt.Printf("%s %s\n", entry.Kind, entry.Path)
+	}
+	return nil
+}
+
+func formatDiffJSON(entries []buildah.DiffEntry) error {
+	jsonEntries := make([]jsonDiffEntry, 0, len(entries))
+	for _, entry := range entries {
+		jsonEntries = append(jsonEntries, jsonDiffEntry{
+			Path: entry.Path,
+			Kind: entry.Kind.String(),
+			Size: entry.Size,
+		})
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "    ")
+	return enc.Encode(jsonEntries)
+}
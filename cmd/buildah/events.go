@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/containers/buildah/pkg/events"
+	"github.com/containers/buildah/pkg/formats"
+	"github.com/containers/common/pkg/timetype"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type eventsOptions struct {
+	since   string
+	until   string
+	filters []string
+	format  string
+	json    bool
+}
+
+type eventsParams struct {
+	Time string
+	Type string
+	ID   string
+	Name string
+	User string
+	Args string
+}
+
+var eventsHeader = map[string]string{
+	"Time": "TIME",
+	"Type": "TYPE",
+	"ID":   "ID",
+	"Name": "NAME",
+	"User": "USER",
+	"Args": "ARGS",
+}
+
+func init() {
+	var (
+		opts              eventsOptions
+		eventsDescription = "\n  Displays the audit log of significant operations (from, run, copy, commit,\n  push, rm) recorded in local storage, for compliance and troubleshooting."
+	)
+	eventsCommand := &cobra.Command{
+		Use:   "events",
+		Short: "Show buildah events",
+		Long:  eventsDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return eventsCmd(cmd, args, opts)
+		},
+		Example: `buildah events
+  buildah events --since 1h
+  buildah events --filter type=commit`,
+		Args: cobra.NoArgs,
+	}
+	eventsCommand.SetUsageTemplate(UsageTemplate())
+
+	flags := eventsCommand.Flags()
+	flags.StringVar(&opts.since, "since", "", "show events created since this timestamp")
+	flags.StringVar(&opts.until, "until", "", "show events created until this timestamp")
+	flags.StringArrayVar(&opts.filters, "filter", []string{}, "filter events, currently only \"type=<from|run|copy|commit|push|rm>\" is supported")
+	flags.StringVar(&opts.format, "format", "", "pretty-print using a Go template")
+	flags.BoolVar(&opts.json, "json", false, "output in JSON format")
+
+	rootCmd.AddCommand(eventsCommand)
+}
+
+func eventsCmd(c *cobra.Command, args []string, iopts eventsOptions) error {
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+
+	var filters []events.Filter
+	if iopts.since != "" {
+		since, err := parseEventTime(iopts.since)
+		if err != nil {
+			return errors.Wrapf(err, "error parsing --since value %q", iopts.since)
+		}
+		filters = append(filters, events.Since(since))
+	}
+	if iopts.until != "" {
+		until, err := parseEventTime(iopts.until)
+		if err != nil {
+			return errors.Wrapf(err, "error parsing --until value %q", iopts.until)
+		}
+		filters = append(filters, events.Until(until))
+	}
+	for _, filter := range iopts.filters {
+		f, err := parseEventFilter(filter)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, f)
+	}
+
+	matched, err := events.Read(store, filters...)
+	if err != nil {
+		return errors.Wrapf(err, "error reading event log")
+	}
+
+	if iopts.json {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "    ")
+		return enc.Encode(matched)
+	}
+
+	params := make([]eventsParams, 0, len(matched))
+	for _, event := range matched {
+		params = append(params, eventsParams{
+			Time: event.Time.Format(time.RFC3339),
+			Type: string(event.Type),
+			ID:   truncateID(event.ID, true),
+			Name: event.Name,
+			User: event.User,
+			Args: strings.Join(event.Args, " "),
+		})
+	}
+	outputFormat := "table {{.Time}}\t{{.Type}}\t{{.ID}}\t{{.Name}}\t{{.User}}\t{{.Args}}"
+	if iopts.format != "" {
+		outputFormat = iopts.format
+	}
+	out := formats.StdoutTemplateArray{Output: eventsToGeneric(params), Template: outputFormat, Fields: eventsHeader}
+	return formats.Writer(out).Out()
+}
+
+// parseEventTime parses a --since/--until value using the same relative and
+// absolute time formats as "podman events" (via containers/common's
+// timetype package), returning an absolute time.
+func parseEventTime(value string) (time.Time, error) {
+	timestamp, err := timetype.GetTimestamp(value, time.Now())
+	if err != nil {
+		return time.Time{}, err
+	}
+	seconds, nanoseconds, err := timetype.ParseTimestamps(timestamp, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, nanoseconds), nil
+}
+
+// parseEventFilter turns a single "--filter key=value" argument into an
+// events.Filter.  Only "type" is currently a recognized key.
+func parseEventFilter(filter string) (events.Filter, error) {
+	kv := strings.SplitN(filter, "=", 2)
+	if len(kv) != 2 {
+		return nil, errors.Errorf("invalid --filter value %q: expected \"key=value\"", filter)
+	}
+	switch kv[0] {
+	case "type":
+		return events.TypeIs(events.Type(kv[1])), nil
+	default:
+		return nil, errors.Errorf("unrecognized --filter key %q", kv[0])
+	}
+}
+
+func eventsToGeneric(params []eventsParams) (genericParams []interface{}) {
+	for _, v := range params {
+		genericParams = append(genericParams, interface{}(v))
+	}
+	return genericParams
+}
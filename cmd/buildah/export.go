@@ -0,0 +1,94 @@
+package main
+
+import (
+	"io"
+	"os"
+
+	buildahcli "github.com/containers/buildah/pkg/cli"
+	"github.com/containers/buildah/pkg/parse"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type exportOptions struct {
+	output string
+}
+
+func init() {
+	var (
+		opts              exportOptions
+		exportDescription = "\n  Streams a working container's or image's flattened root filesystem as a\n  tar archive to stdout, or to a file with --output."
+	)
+	exportCommand := &cobra.Command{
+		Use:   "export",
+		Short: "Export a container's or image's root filesystem as a tar archive",
+		Long:  exportDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exportCmd(cmd, args, opts)
+		},
+		Example: `buildah export containerID > container.tar
+  buildah export --output image.tar imageID`,
+		Args: cobra.ExactArgs(1),
+	}
+	exportCommand.SetUsageTemplate(UsageTemplate())
+
+	flags := exportCommand.Flags()
+	flags.StringVarP(&opts.output, "output", "o", "", "write the archive to `file` instead of stdout")
+
+	rootCmd.AddCommand(exportCommand)
+}
+
+func exportCmd(c *cobra.Command, args []string, iopts exportOptions) error {
+	if err := buildahcli.VerifyFlagsArgsOrder(args); err != nil {
+		return err
+	}
+	name := args[0]
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+	ctx := getContext()
+
+	var topLayer string
+	if builder, err := openBuilder(ctx, store, name); err == nil {
+		container, err := store.Container(builder.ContainerID)
+		if err != nil {
+			return errors.Wrapf(err, "error reading container %q", name)
+		}
+		topLayer = container.LayerID
+	} else {
+		builder, err := openImage(ctx, systemContext, store, name)
+		if err != nil {
+			return errors.Errorf("%q is not a known container or image", name)
+		}
+		image, err := store.Image(builder.FromImageID)
+		if err != nil {
+			return errors.Wrapf(err, "error reading image %q", name)
+		}
+		topLayer = image.TopLayer
+	}
+
+	diff, err := store.Diff("", topLayer, nil)
+	if err != nil {
+		return errors.Wrapf(err, "error exporting %q", name)
+	}
+	defer diff.Close()
+
+	out := os.Stdout
+	if iopts.output != "" {
+		f, err := os.Create(iopts.output)
+		if err != nil {
+			return errors.Wrapf(err, "error creating %q", iopts.output)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	_, err = io.Copy(out, diff)
+	return err
+}
@@ -10,9 +10,14 @@ import (
 	"github.com/containers/buildah"
 	"github.com/containers/buildah/define"
 	buildahcli "github.com/containers/buildah/pkg/cli"
+	"github.com/containers/buildah/pkg/completion"
+	"github.com/containers/buildah/pkg/events"
 	"github.com/containers/buildah/pkg/parse"
+	"github.com/containers/buildah/pkg/pod"
 	"github.com/containers/common/pkg/auth"
+	commonComp "github.com/containers/common/pkg/completion"
 	"github.com/containers/common/pkg/config"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -25,6 +30,7 @@ type fromReply struct {
 	creds           string
 	format          string
 	name            string
+	pod             string
 	pull            bool
 	pullAlways      bool
 	pullNever       bool
@@ -58,6 +64,7 @@ func init() {
 		Example: `buildah from --pull imagename
   buildah from docker-daemon:imagename:imagetag
   buildah from --name "myimagename" myregistry/myrepository/imagename:imagetag`,
+		ValidArgsFunction: completeImages,
 	}
 	fromCommand.SetUsageTemplate(UsageTemplate())
 
@@ -69,6 +76,7 @@ func init() {
 	flags.StringVar(&opts.creds, "creds", "", "use `[username[:password]]` for accessing the registry")
 	flags.StringVarP(&opts.format, "format", "f", defaultFormat(), "`format` of the image manifest and metadata")
 	flags.StringVar(&opts.name, "name", "", "`name` for the working container")
+	flags.StringVar(&opts.pod, "pod", "", "join the network namespace of the named `pod`, creating it if it doesn't already exist")
 	flags.BoolVar(&opts.pull, "pull", true, "pull the image from the registry if newer or not present in store, if false, only pull the image if not present")
 	flags.BoolVar(&opts.pullAlways, "pull-always", false, "pull the image even if the named image is present in store")
 	flags.BoolVar(&opts.pullNever, "pull-never", false, "do not pull the image, use the image present in store if available")
@@ -88,6 +96,11 @@ func init() {
 	flags.AddFlagSet(&fromAndBudFlags)
 	flags.SetNormalizeFunc(buildahcli.AliasFlags)
 
+	flagCompletions := buildahcli.GetFromAndBudFlagsCompletions()
+	flagCompletions["isolation"] = completion.AutocompleteIsolation
+	flagCompletions["format"] = completion.AutocompleteImageFormat
+	commonComp.CompleteCommandFlags(fromCommand, flagCompletions)
+
 	rootCmd.AddCommand(fromCommand)
 }
 
@@ -196,19 +209,8 @@ func fromCmd(c *cobra.Command, args []string, iopts fromReply) error {
 		return errors.Wrapf(err, "error building system context")
 	}
 
-	pullFlagsCount := 0
-	if c.Flag("pull").Changed {
-		pullFlagsCount++
-	}
-	if c.Flag("pull-always").Changed {
-		pullFlagsCount++
-	}
-	if c.Flag("pull-never").Changed {
-		pullFlagsCount++
-	}
-
-	if pullFlagsCount > 1 {
-		return errors.Errorf("can only set one of 'pull' or 'pull-always' or 'pull-never'")
+	if err := buildahcli.ValidatePullFlags(c.Flags()); err != nil {
+		return err
 	}
 
 	pullPolicy := define.PullIfMissing
@@ -243,6 +245,20 @@ func fromCmd(c *cobra.Command, args []string, iopts fromReply) error {
 	if err != nil {
 		return errors.Wrapf(err, "error parsing namespace-related options")
 	}
+	if iopts.pod != "" {
+		if c.Flag("network").Changed {
+			return errors.Errorf("cannot set both --pod and --network")
+		}
+		nsPath, err := pod.EnsureNetNS(store.RunRoot(), iopts.pod)
+		if err != nil {
+			return errors.Wrapf(err, "error joining pod %q", iopts.pod)
+		}
+		namespaceOptions.AddOrReplace(define.NamespaceOption{
+			Name: string(specs.NetworkNamespace),
+			Path: nsPath,
+		})
+		networkPolicy = define.NetworkEnabled
+	}
 	usernsOption, idmappingOptions, err := parse.IDMappingOptions(c, isolation)
 	if err != nil {
 		return errors.Wrapf(err, "error parsing ID mapping options")
@@ -296,6 +312,7 @@ func fromCmd(c *cobra.Command, args []string, iopts fromReply) error {
 		MaxPullRetries:        maxPullPushRetries,
 		PullRetryDelay:        pullPushRetryDelay,
 		OciDecryptConfig:      decConfig,
+		RequirePrimarySource:  iopts.RequirePrimarySource,
 	}
 
 	if !iopts.quiet {
@@ -317,6 +334,10 @@ func fromCmd(c *cobra.Command, args []string, iopts fromReply) error {
 			return errors.Wrapf(err, "filed to write Container ID File %q", filePath)
 		}
 	}
+	if err := events.Write(store, events.Event{Type: events.From, ID: builder.ContainerID, Name: builder.Container, Args: []string{options.FromImage}}); err != nil {
+		logrus.Debugf("error recording from event: %v", err)
+	}
+
 	fmt.Printf("%s\n", builder.Container)
 	return builder.Save()
 }
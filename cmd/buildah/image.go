@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/containers/buildah/pkg/parse"
+	"github.com/containers/common/libimage"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	var (
+		imageDescription = "\n  Manages read-only, containerless views of locally-stored images."
+	)
+	imageCommand := &cobra.Command{
+		Use:   "image",
+		Short: "Manage images",
+		Long:  imageDescription,
+		Example: `buildah image mount imageName
+  buildah image umount imageName`,
+	}
+	imageCommand.SetUsageTemplate(UsageTemplate())
+	rootCmd.AddCommand(imageCommand)
+
+	imageMountDescription := "\n  Mounts the flattened filesystem of a locally-stored image read-only, without\n  creating a working container, for inspection or scanning.\n\n  Note:  In rootless mode you need to first execute buildah unshare, to put you\n  into the usernamespace. Afterwards you can mount the image and view/scan the\n  content of its root file system."
+	imageMountCommand := &cobra.Command{
+		Use:   "mount [image]",
+		Short: "Mount an image's root filesystem read-only",
+		Long:  imageMountDescription,
+		RunE:  imageMountCmd,
+		Example: `buildah image mount imageID
+  buildah unshare
+  buildah image mount imageID`,
+		Args: cobra.ExactArgs(1),
+	}
+	imageMountCommand.SetUsageTemplate(UsageTemplate())
+	imageCommand.AddCommand(imageMountCommand)
+
+	imageUmountDescription := "\n  Unmounts the root filesystem of a locally-stored image that was previously\n  mounted with \"buildah image mount\"."
+	imageUmountCommand := &cobra.Command{
+		Use:     "umount [image]",
+		Aliases: []string{"unmount"},
+		Short:   "Unmount an image's root filesystem",
+		Long:    imageUmountDescription,
+		RunE:    imageUmountCmd,
+		Example: `buildah image umount imageID`,
+		Args:    cobra.ExactArgs(1),
+	}
+	imageUmountCommand.SetUsageTemplate(UsageTemplate())
+	imageCommand.AddCommand(imageUmountCommand)
+}
+
+func imageRuntime(c *cobra.Command) (*libimage.Runtime, error) {
+	store, err := getStore(c)
+	if err != nil {
+		return nil, err
+	}
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error building system context")
+	}
+	return libimage.RuntimeFromStore(store, &libimage.RuntimeOptions{SystemContext: systemContext})
+}
+
+func imageMountCmd(c *cobra.Command, args []string) error {
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+	if os.Geteuid() != 0 && store.GraphDriverName() != "vfs" {
+		return errors.Errorf("cannot mount using driver %s in rootless mode. You need to run it in a `buildah unshare` session", store.GraphDriverName())
+	}
+	runtime, err := imageRuntime(c)
+	if err != nil {
+		return err
+	}
+	image, _, err := runtime.LookupImage(args[0], nil)
+	if err != nil {
+		return err
+	}
+	mountPoint, err := image.Mount(getContext(), nil, "")
+	if err != nil {
+		return errors.Wrapf(err, "error mounting image %q", args[0])
+	}
+	fmt.Printf("%s\n", mountPoint)
+	return nil
+}
+
+func imageUmountCmd(c *cobra.Command, args []string) error {
+	runtime, err := imageRuntime(c)
+	if err != nil {
+		return err
+	}
+	image, _, err := runtime.LookupImage(args[0], nil)
+	if err != nil {
+		return err
+	}
+	if err := image.Unmount(false); err != nil {
+		return errors.Wrapf(err, "error unmounting image %q", args[0])
+	}
+	return nil
+}
@@ -52,6 +52,8 @@ type imageOptions struct {
 	quiet     bool
 	readOnly  bool
 	history   bool
+	schema    bool
+	columns   string
 }
 
 type imageResults struct {
@@ -59,6 +61,10 @@ type imageResults struct {
 	filter string
 }
 
+// defaultImageColumns are the columns used with --format csv/tsv if
+// --columns wasn't also given.
+var defaultImageColumns = []string{"Name", "Tag", "ID", "CreatedAt", "Size"}
+
 var imagesHeader = map[string]string{
 	"Name":      "REPOSITORY",
 	"Tag":       "TAG",
@@ -99,11 +105,16 @@ func init() {
 	flags.BoolVar(&opts.truncate, "no-trunc", false, "do not truncate output")
 	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "display only image IDs")
 	flags.BoolVarP(&opts.history, "history", "", false, "display the image name history")
+	flags.BoolVar(&opts.schema, "schema", false, "print the JSON Schema for --json output and exit")
+	flags.StringVar(&opts.columns, "columns", "", fmt.Sprintf("comma-separated list of columns to include with --format csv/tsv (default %q)", strings.Join(defaultImageColumns, ",")))
 
 	rootCmd.AddCommand(imagesCommand)
 }
 
 func imagesCmd(c *cobra.Command, args []string, iopts *imageResults) error {
+	if iopts.schema {
+		return printJSONSchema(jsonImage{}, "buildah-images")
+	}
 	if len(args) > 0 {
 		if iopts.all {
 			return errors.Errorf("when using the --all switch, you may not pass any images names or IDs")
@@ -158,12 +169,17 @@ func imagesCmd(c *cobra.Command, args []string, iopts *imageResults) error {
 		truncate:  !iopts.truncate,
 		quiet:     iopts.quiet,
 		history:   iopts.history,
+		columns:   iopts.columns,
 	}
 
 	if opts.json {
 		return formatImagesJSON(images, opts)
 	}
 
+	if opts.format == "csv" || opts.format == "tsv" {
+		return formatImagesDSV(images, opts)
+	}
+
 	return formatImages(images, opts)
 }
 
@@ -235,14 +251,14 @@ func (a imagesSorted) Swap(i, j int) {
 	a[i], a[j] = a[j], a[i]
 }
 
-func formatImages(images []*libimage.Image, opts imageOptions) error {
+func buildImageOutputData(images []*libimage.Image, opts imageOptions) (imagesSorted, error) {
 	var outputData imagesSorted
 
 	for _, image := range images {
 		var outputParam imageOutputParams
 		size, err := image.Size()
 		if err != nil {
-			return err
+			return nil, err
 		}
 		created := image.Created()
 		outputParam.CreatedAtRaw = created
@@ -254,12 +270,12 @@ func formatImages(images []*libimage.Image, opts imageOptions) error {
 
 		repoTags, err := image.NamedRepoTags()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		nameTagPairs, err := libimage.ToNameTagPairs(repoTags)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		for _, pair := range nameTagPairs {
@@ -276,10 +292,39 @@ func formatImages(images []*libimage.Image, opts imageOptions) error {
 	}
 
 	sort.Sort(outputData)
+	return outputData, nil
+}
+
+func formatImages(images []*libimage.Image, opts imageOptions) error {
+	outputData, err := buildImageOutputData(images, opts)
+	if err != nil {
+		return err
+	}
 	out := formats.StdoutTemplateArray{Output: imagesToGeneric(outputData), Template: outputHeader(opts), Fields: imagesHeader}
 	return formats.Writer(out).Out()
 }
 
+// formatImagesDSV writes images as delimiter-separated values, using
+// opts.format ("csv" or "tsv") to select the delimiter and opts.columns, if
+// set, to select and order the columns; otherwise defaultImageColumns is
+// used.
+func formatImagesDSV(images []*libimage.Image, opts imageOptions) error {
+	outputData, err := buildImageOutputData(images, opts)
+	if err != nil {
+		return err
+	}
+	columns := defaultImageColumns
+	if opts.columns != "" {
+		columns = strings.Split(opts.columns, ",")
+	}
+	delimiter := ','
+	if opts.format == "tsv" {
+		delimiter = '\t'
+	}
+	out := formats.DSVStructArray{Output: imagesToGeneric(outputData), Columns: columns, Headers: imagesHeader, Delimiter: delimiter}
+	return formats.Writer(out).Out()
+}
+
 func formatHistory(history []string, name, tag string) string {
 	if len(history) == 0 {
 		return none
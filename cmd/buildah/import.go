@@ -0,0 +1,173 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/containers/buildah"
+	"github.com/containers/buildah/define"
+	buildahcli "github.com/containers/buildah/pkg/cli"
+	"github.com/containers/buildah/pkg/parse"
+	"github.com/containers/image/v5/pkg/shortnames"
+	storageTransport "github.com/containers/image/v5/storage"
+	"github.com/containers/image/v5/transports/alltransports"
+	"github.com/containers/image/v5/types"
+	"github.com/containers/storage/pkg/archive"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type importOptions struct {
+	changes []string
+}
+
+func init() {
+	var (
+		opts              importOptions
+		importDescription = "\n  Creates an image from the contents of a root filesystem tarball, optionally\n  applying Dockerfile-instruction-style configuration changes."
+	)
+	importCommand := &cobra.Command{
+		Use:   "import",
+		Short: "Create an image from a tarball of a root filesystem",
+		Long:  importDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return importCmd(cmd, args, opts)
+		},
+		Example: `buildah import rootfs.tar
+  buildah import --change "ENV DEBUG=true" rootfs.tar newImageName
+  cat rootfs.tar | buildah import -`,
+		Args: cobra.RangeArgs(1, 2),
+	}
+	importCommand.SetUsageTemplate(UsageTemplate())
+
+	flags := importCommand.Flags()
+	flags.StringArrayVar(&opts.changes, "change", []string{}, "apply a Dockerfile-instruction-style `change` (ENV, LABEL, USER, WORKDIR, VOLUME, EXPOSE, CMD, or ENTRYPOINT) to the imported image")
+
+	rootCmd.AddCommand(importCommand)
+}
+
+func importCmd(c *cobra.Command, args []string, iopts importOptions) error {
+	if err := buildahcli.VerifyFlagsArgsOrder(args); err != nil {
+		return err
+	}
+	source := args[0]
+	image := ""
+	if len(args) > 1 {
+		image = args[1]
+	}
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+	ctx := getContext()
+
+	builder, err := buildah.NewBuilder(ctx, store, buildah.BuilderOptions{
+		FromImage:     "scratch",
+		SystemContext: systemContext,
+		Mount:         true,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error creating working container")
+	}
+	defer func() {
+		if err := builder.Delete(); err != nil {
+			fmt.Fprintf(os.Stderr, "error removing temporary working container: %v\n", err)
+		}
+	}()
+
+	in := os.Stdin
+	if source != "-" {
+		f, err := os.Open(source)
+		if err != nil {
+			return errors.Wrapf(err, "error opening %q", source)
+		}
+		defer f.Close()
+		in = f
+	}
+	if err := archive.Untar(in, builder.MountPoint, nil); err != nil {
+		return errors.Wrapf(err, "error extracting %q", source)
+	}
+
+	for _, change := range iopts.changes {
+		if err := applyImportChange(builder, change); err != nil {
+			return err
+		}
+	}
+
+	if err := builder.Unmount(); err != nil {
+		return errors.Wrapf(err, "error unmounting working container")
+	}
+
+	var dest types.ImageReference
+	if image != "" {
+		if dest, err = alltransports.ParseImageName(image); err != nil {
+			candidates, err := shortnames.ResolveLocally(systemContext, image)
+			if err != nil {
+				return err
+			}
+			if len(candidates) == 0 {
+				return errors.Errorf("error parsing target image name %q", image)
+			}
+			dest2, err2 := storageTransport.Transport.ParseStoreReference(store, candidates[0].String())
+			if err2 != nil {
+				return errors.Wrapf(err, "error parsing target image name %q", image)
+			}
+			dest = dest2
+		}
+	}
+
+	builder.SetLabel(buildah.BuilderIdentityAnnotation, define.Version)
+	id, _, _, err := builder.Commit(ctx, dest, buildah.CommitOptions{
+		SystemContext: systemContext,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error committing imported rootfs to %q", image)
+	}
+	fmt.Printf("%s\n", id)
+	return nil
+}
+
+// applyImportChange applies a single Docker-"import --change"-style
+// instruction, in "INSTRUCTION value" format, to builder's configuration.
+func applyImportChange(builder *buildah.Builder, change string) error {
+	fields := strings.SplitN(strings.TrimSpace(change), " ", 2)
+	if len(fields) != 2 {
+		return errors.Errorf("invalid --change value %q: expected \"INSTRUCTION value\"", change)
+	}
+	instruction, value := strings.ToUpper(fields[0]), strings.TrimSpace(fields[1])
+	switch instruction {
+	case "ENV":
+		av := strings.SplitN(value, "=", 2)
+		if len(av) != 2 {
+			return errors.Errorf("invalid --change value %q: expected \"ENV name=value\"", change)
+		}
+		builder.SetEnv(av[0], av[1])
+	case "LABEL":
+		av := strings.SplitN(value, "=", 2)
+		if len(av) != 2 {
+			return errors.Errorf("invalid --change value %q: expected \"LABEL name=value\"", change)
+		}
+		builder.SetLabel(av[0], av[1])
+	case "USER":
+		builder.SetUser(value)
+	case "WORKDIR":
+		builder.SetWorkDir(value)
+	case "VOLUME":
+		builder.AddVolume(value)
+	case "EXPOSE":
+		builder.SetPort(value)
+	case "CMD":
+		return updateCmd(builder, value)
+	case "ENTRYPOINT":
+		updateEntrypoint(builder, value)
+	default:
+		return errors.Errorf("unrecognized --change instruction %q", instruction)
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/containers/buildah"
 	buildahcli "github.com/containers/buildah/pkg/cli"
 	"github.com/containers/buildah/pkg/parse"
+	"github.com/containers/storage"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh/terminal"
@@ -22,8 +23,10 @@ const (
 )
 
 type inspectResults struct {
-	format      string
-	inspectType string
+	format         string
+	inspectType    string
+	sizeBreakdown  bool
+	sizeBreakdownN int
 }
 
 func init() {
@@ -41,7 +44,8 @@ func init() {
 		},
 		Example: `buildah inspect containerID
   buildah inspect --type image imageID
-  buildah inspect --format '{{.OCIv1.Config.Env}}' alpine`,
+  buildah inspect --format '{{.OCIv1.Config.Env}}' alpine
+  buildah inspect --type image --size-breakdown alpine`,
 	}
 	inspectCommand.SetUsageTemplate(UsageTemplate())
 
@@ -49,6 +53,8 @@ func init() {
 	flags.SetInterspersed(false)
 	flags.StringVarP(&opts.format, "format", "f", "", "use `format` as a Go template to format the output")
 	flags.StringVarP(&opts.inspectType, "type", "t", inspectTypeContainer, "look at the item of the specified `type` (container or image) and name")
+	flags.BoolVar(&opts.sizeBreakdown, "size-breakdown", false, "report the largest files and directories in the image, per layer and overall, instead of its configuration")
+	flags.IntVar(&opts.sizeBreakdownN, "top", 10, "with --size-breakdown, the `number` of largest files/directories to report for each list")
 
 	rootCmd.AddCommand(inspectCommand)
 }
@@ -65,6 +71,9 @@ func inspectCmd(c *cobra.Command, args []string, iopts inspectResults) error {
 	if len(args) > 1 {
 		return errors.Errorf("too many arguments specified")
 	}
+	if iopts.sizeBreakdown && iopts.inspectType != inspectTypeImage {
+		return errors.Errorf("--size-breakdown is only supported with --type %s", inspectTypeImage)
+	}
 
 	systemContext, err := parse.SystemContextFromOptions(c)
 	if err != nil {
@@ -100,6 +109,9 @@ func inspectCmd(c *cobra.Command, args []string, iopts inspectResults) error {
 		if err != nil {
 			return err
 		}
+		if iopts.sizeBreakdown {
+			return inspectSizeBreakdown(store, builder.FromImageID, iopts)
+		}
 	case inspectTypeManifest:
 		return manifestInspect(ctx, store, systemContext, name)
 	default:
@@ -107,23 +119,7 @@ func inspectCmd(c *cobra.Command, args []string, iopts inspectResults) error {
 	}
 	out := buildah.GetBuildInfo(builder)
 	if iopts.format != "" {
-		format := iopts.format
-		if matched, err := regexp.MatchString("{{.*}}", format); err != nil {
-			return errors.Wrapf(err, "error validating format provided: %s", format)
-		} else if !matched {
-			return errors.Errorf("error invalid format provided: %s", format)
-		}
-		t, err := template.New("format").Parse(format)
-		if err != nil {
-			return errors.Wrapf(err, "Template parsing error")
-		}
-		if err = t.Execute(os.Stdout, out); err != nil {
-			return err
-		}
-		if terminal.IsTerminal(int(os.Stdout.Fd())) {
-			fmt.Println()
-		}
-		return nil
+		return executeFormatTemplate(iopts.format, out)
 	}
 
 	enc := json.NewEncoder(os.Stdout)
@@ -133,3 +129,62 @@ func inspectCmd(c *cobra.Command, args []string, iopts inspectResults) error {
 	}
 	return enc.Encode(out)
 }
+
+// executeFormatTemplate parses format as a Go template and executes it
+// against data, printing the result to stdout.
+func executeFormatTemplate(format string, data interface{}) error {
+	if matched, err := regexp.MatchString("{{.*}}", format); err != nil {
+		return errors.Wrapf(err, "error validating format provided: %s", format)
+	} else if !matched {
+		return errors.Errorf("error invalid format provided: %s", format)
+	}
+	t, err := template.New("format").Parse(format)
+	if err != nil {
+		return errors.Wrapf(err, "Template parsing error")
+	}
+	if err := t.Execute(os.Stdout, data); err != nil {
+		return err
+	}
+	if terminal.IsTerminal(int(os.Stdout.Fd())) {
+		fmt.Println()
+	}
+	return nil
+}
+
+// inspectSizeBreakdown reports the largest files and directories that make
+// up the given image, without needing to mount it, by walking the diff of
+// each of its layers.
+func inspectSizeBreakdown(store storage.Store, imageID string, iopts inspectResults) error {
+	storeImage, err := store.Image(imageID)
+	if err != nil {
+		return errors.Wrapf(err, "error locating image %q", imageID)
+	}
+
+	breakdown, err := buildah.GetImageSizeBreakdown(store, *storeImage, iopts.sizeBreakdownN)
+	if err != nil {
+		return errors.Wrapf(err, "error computing size breakdown for image %q", imageID)
+	}
+
+	if iopts.format != "" {
+		return executeFormatTemplate(iopts.format, breakdown)
+	}
+
+	fmt.Printf("Largest directories in %s:\n", imageID)
+	for _, entry := range breakdown.TopDirectories {
+		fmt.Printf("%12d  %s\n", entry.Size, entry.Path)
+	}
+	fmt.Printf("\nLargest files in %s:\n", imageID)
+	for _, entry := range breakdown.TopFiles {
+		fmt.Printf("%12d  %s\n", entry.Size, entry.Path)
+	}
+	for _, layer := range breakdown.PerLayer {
+		if len(layer.TopFiles) == 0 {
+			continue
+		}
+		fmt.Printf("\nLargest files added by layer %s:\n", layer.ID)
+		for _, entry := range layer.TopFiles {
+			fmt.Printf("%12d  %s\n", entry.Size, entry.Path)
+		}
+	}
+	return nil
+}
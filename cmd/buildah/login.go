@@ -1,10 +1,17 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"sort"
 
+	"github.com/containers/buildah/pkg/formats"
 	"github.com/containers/buildah/pkg/parse"
 	"github.com/containers/common/pkg/auth"
+	"github.com/containers/image/v5/pkg/docker/config"
+	"github.com/containers/image/v5/pkg/sysregistriesv2"
+	"github.com/containers/image/v5/types"
+	"github.com/docker/docker-credential-helpers/client"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
@@ -13,6 +20,17 @@ type loginReply struct {
 	loginOpts auth.LoginOptions
 	getLogin  bool
 	tlsVerify bool
+	list      bool
+}
+
+type loginListParams struct {
+	Registry string
+	Store    string
+}
+
+var loginListHeader = map[string]string{
+	"Registry": "REGISTRY",
+	"Store":    "CREDENTIAL STORE",
 }
 
 func init() {
@@ -39,11 +57,24 @@ func init() {
 	flags.SetInterspersed(false)
 	flags.BoolVar(&opts.tlsVerify, "tls-verify", true, "require HTTPS and verify certificates when accessing the registry. TLS verification cannot be used when talking to an insecure registry.")
 	flags.BoolVar(&opts.getLogin, "get-login", true, "Return the current login user for the registry")
+	flags.BoolVar(&opts.list, "list", false, "list registries with stored credentials, and which credential store holds each one, instead of logging in")
 	flags.AddFlagSet(auth.GetLoginFlags(&opts.loginOpts))
 	rootCmd.AddCommand(loginCommand)
 }
 
 func loginCmd(c *cobra.Command, args []string, iopts *loginReply) error {
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+
+	if iopts.list {
+		if len(args) > 0 {
+			return errors.Errorf("--list takes no arguments")
+		}
+		return loginListCmd(systemContext)
+	}
+
 	if len(args) > 1 {
 		return errors.Errorf("too many arguments, login takes only 1 argument")
 	}
@@ -55,11 +86,67 @@ func loginCmd(c *cobra.Command, args []string, iopts *loginReply) error {
 		return err
 	}
 
-	systemContext, err := parse.SystemContextFromOptions(c)
-	if err != nil {
-		return errors.Wrapf(err, "error building system context")
-	}
 	ctx := getContext()
 	iopts.loginOpts.GetLoginSet = c.Flag("get-login").Changed
 	return auth.Login(ctx, systemContext, &iopts.loginOpts, args)
 }
+
+// loginListCmd reports, for every registry with stored credentials, which
+// credential store (an external Docker credential helper, or the plaintext
+// auth file) holds them, so that "buildah login --list" can answer that
+// question without the caller needing to inspect registries.conf or any
+// individual helper themselves.
+func loginListCmd(systemContext *types.SystemContext) error {
+	helpers, err := sysregistriesv2.CredentialHelpers(systemContext)
+	if err != nil {
+		return errors.Wrapf(err, "error determining configured credential helpers")
+	}
+
+	store := make(map[string]string)
+	for _, helper := range helpers {
+		if helper == sysregistriesv2.AuthenticationFileHelper {
+			continue
+		}
+		registries, err := client.List(client.NewShellProgramFunc(fmt.Sprintf("docker-credential-%s", helper)))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error listing credentials stored by helper %q: %v\n", helper, err)
+			continue
+		}
+		for registry := range registries {
+			if _, ok := store[registry]; !ok {
+				store[registry] = helper
+			}
+		}
+	}
+
+	// Any registry with valid credentials that wasn't claimed by an
+	// external helper above must be coming from the plaintext auth file.
+	all, err := config.GetAllCredentials(systemContext)
+	if err != nil {
+		return errors.Wrapf(err, "error reading stored credentials")
+	}
+	for registry := range all {
+		if _, ok := store[registry]; !ok {
+			store[registry] = "auth file"
+		}
+	}
+
+	if len(store) == 0 {
+		return nil
+	}
+
+	params := make([]loginListParams, 0, len(store))
+	for registry, helper := range store {
+		params = append(params, loginListParams{Registry: registry, Store: helper})
+	}
+	sort.Slice(params, func(i, j int) bool {
+		return params[i].Registry < params[j].Registry
+	})
+
+	genericParams := make([]interface{}, 0, len(params))
+	for _, p := range params {
+		genericParams = append(genericParams, p)
+	}
+	out := formats.StdoutTemplateArray{Output: genericParams, Template: "table {{.Registry}}\t{{.Store}}", Fields: loginListHeader}
+	return formats.Writer(out).Out()
+}
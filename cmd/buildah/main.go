@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"runtime/pprof"
+	"runtime/trace"
 	"strings"
 	"syscall"
 
@@ -38,7 +40,12 @@ type globalFlags struct {
 	CPUProfile                 string
 	cpuProfileFile             *os.File
 	MemoryProfile              string
+	Trace                      string
+	traceFile                  *os.File
+	ProfileDir                 string
 	UserShortNameAliasConfPath string
+	Connection                 string
+	Tmpdir                     string
 }
 
 var rootCmd = &cobra.Command{
@@ -48,6 +55,9 @@ var rootCmd = &cobra.Command{
 		return cmd.Help()
 	},
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if globalFlagResults.Connection != "" {
+			return runOnConnection(cmd, globalFlagResults.Connection)
+		}
 		return before(cmd)
 	},
 	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
@@ -97,6 +107,10 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&globalFlagResults.LogLevel, logLevel, "warn", `The log level to be used. Either "trace", "debug", "info", "warn", "error", "fatal", or "panic".`)
 	rootCmd.PersistentFlags().StringVar(&globalFlagResults.CPUProfile, "cpu-profile", "", "`file` to write CPU profile")
 	rootCmd.PersistentFlags().StringVar(&globalFlagResults.MemoryProfile, "memory-profile", "", "`file` to write memory profile")
+	rootCmd.PersistentFlags().StringVar(&globalFlagResults.Trace, "trace", "", "`file` to write a runtime/trace trace")
+	rootCmd.PersistentFlags().StringVar(&globalFlagResults.ProfileDir, "profile-dir", "", "`directory` in which to bundle CPU, memory, and trace profiles instead of writing them to the paths given by -cpu-profile, -memory-profile, and -trace")
+	rootCmd.PersistentFlags().StringVar(&globalFlagResults.Connection, "connection", "", "run the command on the named remote `connection` (see containers.conf) instead of locally")
+	rootCmd.PersistentFlags().StringVar(&globalFlagResults.Tmpdir, "tmpdir", "", "`directory` to use for staging blobs during pull, push, and commit, instead of $TMPDIR or /var/tmp")
 
 	if err := rootCmd.PersistentFlags().MarkHidden("cpu-profile"); err != nil {
 		logrus.Fatalf("unable to mark cpu-profile flag as hidden: %v", err)
@@ -110,6 +124,12 @@ func init() {
 	if err := rootCmd.PersistentFlags().MarkHidden("memory-profile"); err != nil {
 		logrus.Fatalf("unable to mark memory-profile flag as hidden: %v", err)
 	}
+	if err := rootCmd.PersistentFlags().MarkHidden("trace"); err != nil {
+		logrus.Fatalf("unable to mark trace flag as hidden: %v", err)
+	}
+	if err := rootCmd.PersistentFlags().MarkHidden("profile-dir"); err != nil {
+		logrus.Fatalf("unable to mark profile-dir flag as hidden: %v", err)
+	}
 }
 
 func initConfig() {
@@ -138,6 +158,20 @@ func before(cmd *cobra.Command) error {
 		return nil
 	}
 	unshare.MaybeReexecUsingUserNamespace(false)
+	if globalFlagResults.ProfileDir != "" {
+		if err := os.MkdirAll(globalFlagResults.ProfileDir, 0700); err != nil {
+			logrus.Fatalf("could not create profile bundle directory %s: %v", globalFlagResults.ProfileDir, err)
+		}
+		if globalFlagResults.CPUProfile == "" {
+			globalFlagResults.CPUProfile = filepath.Join(globalFlagResults.ProfileDir, "cpu.pprof")
+		}
+		if globalFlagResults.MemoryProfile == "" {
+			globalFlagResults.MemoryProfile = filepath.Join(globalFlagResults.ProfileDir, "memory.pprof")
+		}
+		if globalFlagResults.Trace == "" {
+			globalFlagResults.Trace = filepath.Join(globalFlagResults.ProfileDir, "trace.out")
+		}
+	}
 	if globalFlagResults.CPUProfile != "" {
 		globalFlagResults.cpuProfileFile, err = os.Create(globalFlagResults.CPUProfile)
 		if err != nil {
@@ -147,6 +181,15 @@ func before(cmd *cobra.Command) error {
 			logrus.Fatalf("error starting CPU profiling: %v", err)
 		}
 	}
+	if globalFlagResults.Trace != "" {
+		globalFlagResults.traceFile, err = os.Create(globalFlagResults.Trace)
+		if err != nil {
+			logrus.Fatalf("could not create trace file %s: %v", globalFlagResults.Trace, err)
+		}
+		if err = trace.Start(globalFlagResults.traceFile); err != nil {
+			logrus.Fatalf("error starting trace: %v", err)
+		}
+	}
 
 	defaultContainerConfig, err := config.Default()
 	if err != nil {
@@ -168,6 +211,17 @@ func before(cmd *cobra.Command) error {
 		}
 	}
 
+	tmpdir := globalFlagResults.Tmpdir
+	if tmpdir == "" {
+		tmpdir = parse.GetTempDir()
+	}
+	if err := parse.ValidateTmpDir(tmpdir); err != nil {
+		return err
+	}
+	if err := os.Setenv("TMPDIR", tmpdir); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -199,6 +253,10 @@ func after(cmd *cobra.Command) error {
 		pprof.StopCPUProfile()
 		globalFlagResults.cpuProfileFile.Close()
 	}
+	if globalFlagResults.Trace != "" {
+		trace.Stop()
+		globalFlagResults.traceFile.Close()
+	}
 	if globalFlagResults.MemoryProfile != "" {
 		memoryProfileFile, err := os.Create(globalFlagResults.MemoryProfile)
 		if err != nil {
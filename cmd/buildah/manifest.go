@@ -7,7 +7,11 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"text/template"
 
 	"github.com/containers/buildah/pkg/cli"
 	"github.com/containers/buildah/pkg/parse"
@@ -16,7 +20,9 @@ import (
 	"github.com/containers/common/libimage/manifests"
 	"github.com/containers/common/pkg/auth"
 	cp "github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/image"
 	"github.com/containers/image/v5/manifest"
+	nonecache "github.com/containers/image/v5/pkg/blobinfocache/none"
 	"github.com/containers/image/v5/transports"
 	"github.com/containers/image/v5/transports/alltransports"
 	"github.com/containers/image/v5/types"
@@ -27,23 +33,42 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
 type manifestCreateOpts = struct {
-	os, arch string
-	all      bool
+	os, arch     string
+	all          bool
+	amend        bool
+	artifact     bool
+	artifactType string
 }
 type manifestAddOpts = struct {
 	authfile, certDir, creds, os, arch, variant, osVersion string
 	features, osFeatures, annotations                      []string
 	tlsVerify, all                                         bool
+	attestationFor                                         string
 }
-type manifestRemoveOpts = struct{}
+type manifestRemoveOpts = struct {
+	platform string
+}
+type manifestExistsOpts = struct {
+	platform string
+}
+type manifestPruneOpts = struct{}
 type manifestAnnotateOpts = struct {
 	os, arch, variant, osVersion      string
 	features, osFeatures, annotations []string
+	index                             bool
+	artifactType, subject             string
+}
+type manifestInspectOpts = struct {
+	format string
+}
+type manifestMergeOpts = struct {
+	onConflict string
 }
-type manifestInspectOpts = struct{}
+type manifestDiffOpts = struct{}
 
 func init() {
 	var (
@@ -51,15 +76,23 @@ func init() {
 		manifestCreateDescription   = "\n  Creates manifest lists and image indexes."
 		manifestAddDescription      = "\n  Adds an image to a manifest list or image index."
 		manifestRemoveDescription   = "\n  Removes an image from a manifest list or image index."
+		manifestExistsDescription   = "\n  Checks if a manifest list exists, optionally requiring an instance for a given platform, for use in scripts."
 		manifestAnnotateDescription = "\n  Adds or updates information about an entry in a manifest list or image index."
+		manifestPruneDescription    = "\n  Removes entries from a manifest list or image index whose images no longer exist in local storage."
 		manifestInspectDescription  = "\n  Display the contents of a manifest list or image index."
+		manifestMergeDescription    = "\n  Merges the instances from one or more manifest lists or image indexes into a destination list, creating it if necessary."
+		manifestDiffDescription     = "\n  Shows the platform instances that differ between two manifest lists or image indexes."
 		manifestPushDescription     = "\n  Pushes manifest lists and image indexes to registries."
 		manifestRmDescription       = "\n  Remove one or more manifest lists from local storage."
 		manifestCreateOpts          manifestCreateOpts
 		manifestAddOpts             manifestAddOpts
 		manifestRemoveOpts          manifestRemoveOpts
+		manifestExistsOpts          manifestExistsOpts
+		manifestPruneOpts           manifestPruneOpts
 		manifestAnnotateOpts        manifestAnnotateOpts
 		manifestInspectOpts         manifestInspectOpts
+		manifestMergeOpts           manifestMergeOpts
+		manifestDiffOpts            manifestDiffOpts
 		manifestPushOpts            pushOptions
 	)
 	manifestCommand := &cobra.Command{
@@ -87,12 +120,14 @@ func init() {
 		},
 		Example: `buildah manifest create mylist:v1.11
   buildah manifest create mylist:v1.11 arch-specific-image-to-add
-  buildah manifest create --all mylist:v1.11 transport:tagged-image-to-add`,
+  buildah manifest create --all mylist:v1.11 transport:tagged-image-to-add
+  buildah manifest create --amend mylist:v1.11 'myapp:1.0-*-arch'`,
 		Args: cobra.MinimumNArgs(1),
 	}
 	manifestCreateCommand.SetUsageTemplate(UsageTemplate())
 	flags := manifestCreateCommand.Flags()
 	flags.BoolVar(&manifestCreateOpts.all, "all", false, "add all of the lists' images if the images to add are lists")
+	flags.BoolVar(&manifestCreateOpts.amend, "amend", false, "modify an existing list if one with the given name already exists")
 	flags.StringVar(&manifestCreateOpts.os, "os", "", "if any of the specified images is a list, choose the one for `os`")
 	if err := flags.MarkHidden("os"); err != nil {
 		panic(fmt.Sprintf("error marking --os as hidden: %v", err))
@@ -101,6 +136,8 @@ func init() {
 	if err := flags.MarkHidden("arch"); err != nil {
 		panic(fmt.Sprintf("error marking --arch as hidden: %v", err))
 	}
+	flags.BoolVar(&manifestCreateOpts.artifact, "artifact", false, "mark the list as an OCI artifact index rather than an image index")
+	flags.StringVar(&manifestCreateOpts.artifactType, "artifact-type", "", "`type` of the OCI artifact carried by the list, recorded as an index annotation (requires --artifact)")
 	flags.SetNormalizeFunc(cli.AliasFlags)
 	manifestCommand.AddCommand(manifestCreateCommand)
 
@@ -112,8 +149,10 @@ func init() {
 			return manifestAddCmd(cmd, args, manifestAddOpts)
 		},
 		Example: `buildah manifest add mylist:v1.11 image:v1.11-amd64
-  buildah manifest add mylist:v1.11 transport:imageName`,
-		Args: cobra.MinimumNArgs(2),
+  buildah manifest add mylist:v1.11 transport:imageName
+  buildah manifest add --attestation-for sha256:0e7a99f6d5245b... mylist:v1.11 attestation-image:v1.11`,
+		Args:              cobra.MinimumNArgs(2),
+		ValidArgsFunction: completeImages,
 	}
 	manifestAddCommand.SetUsageTemplate(UsageTemplate())
 	flags = manifestAddCommand.Flags()
@@ -129,6 +168,7 @@ func init() {
 	flags.StringSliceVar(&manifestAddOpts.annotations, "annotation", nil, "set an `annotation` for the specified image")
 	flags.BoolVar(&manifestAddOpts.tlsVerify, "tls-verify", true, "require HTTPS and verify certificates when accessing the registry. TLS verification cannot be used when talking to an insecure registry.")
 	flags.BoolVar(&manifestAddOpts.all, "all", false, "add all of the list's images if the image is a list")
+	flags.StringVar(&manifestAddOpts.attestationFor, "attestation-for", "", "mark the added image as an in-toto attestation manifest for the instance with the given `digest`, using the annotations that registries and \"docker buildx imagetools\" expect")
 	flags.SetNormalizeFunc(cli.AliasFlags)
 	manifestCommand.AddCommand(manifestAddCommand)
 
@@ -139,12 +179,44 @@ func init() {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return manifestRemoveCmd(cmd, args, manifestRemoveOpts)
 		},
-		Example: `buildah manifest remove mylist:v1.11 sha256:15352d97781ffdf357bf3459c037be3efac4133dc9070c2dce7eca7c05c3e736`,
-		Args:    cobra.MinimumNArgs(2),
+		Example: `buildah manifest remove mylist:v1.11 sha256:15352d97781ffdf357bf3459c037be3efac4133dc9070c2dce7eca7c05c3e736
+  buildah manifest remove --platform linux/s390x mylist:v1.11`,
+		Args: cobra.RangeArgs(1, 2),
 	}
+	flags = manifestRemoveCommand.Flags()
+	flags.StringVar(&manifestRemoveOpts.platform, "platform", "", "remove the instance matching `os/arch[/variant]` instead of naming it by digest")
 	manifestRemoveCommand.SetUsageTemplate(UsageTemplate())
 	manifestCommand.AddCommand(manifestRemoveCommand)
 
+	manifestExistsCommand := &cobra.Command{
+		Use:   "exists",
+		Short: "Check if a manifest list exists",
+		Long:  manifestExistsDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return manifestExistsCmd(cmd, args, manifestExistsOpts)
+		},
+		Example: `buildah manifest exists mylist:v1.11
+  buildah manifest exists --platform linux/s390x mylist:v1.11`,
+		Args: cobra.ExactArgs(1),
+	}
+	flags = manifestExistsCommand.Flags()
+	flags.StringVar(&manifestExistsOpts.platform, "platform", "", "also require an instance matching `os/arch[/variant]` to exist in the list")
+	manifestExistsCommand.SetUsageTemplate(UsageTemplate())
+	manifestCommand.AddCommand(manifestExistsCommand)
+
+	manifestPruneCommand := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove entries whose images no longer exist in local storage",
+		Long:  manifestPruneDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return manifestPruneCmd(cmd, args, manifestPruneOpts)
+		},
+		Example: `buildah manifest prune mylist:v1.11`,
+		Args:    cobra.ExactArgs(1),
+	}
+	manifestPruneCommand.SetUsageTemplate(UsageTemplate())
+	manifestCommand.AddCommand(manifestPruneCommand)
+
 	manifestAnnotateCommand := &cobra.Command{
 		Use:   "annotate",
 		Short: "Add or update information about an entry in a manifest list or image index",
@@ -152,8 +224,9 @@ func init() {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return manifestAnnotateCmd(cmd, args, manifestAnnotateOpts)
 		},
-		Example: `buildah manifest annotate --annotation left=right mylist:v1.11 image:v1.11-amd64`,
-		Args:    cobra.MinimumNArgs(2),
+		Example: `buildah manifest annotate --annotation left=right mylist:v1.11 image:v1.11-amd64
+  buildah manifest annotate --index --annotation left=right mylist:v1.11`,
+		Args: cobra.RangeArgs(1, 2),
 	}
 	flags = manifestAnnotateCommand.Flags()
 	flags.StringVar(&manifestAnnotateOpts.os, "os", "", "override the `OS` of the specified image")
@@ -163,6 +236,9 @@ func init() {
 	flags.StringSliceVar(&manifestAnnotateOpts.features, "features", nil, "override the `features` of the specified image")
 	flags.StringSliceVar(&manifestAnnotateOpts.osFeatures, "os-features", nil, "override the os `features` of the specified image")
 	flags.StringSliceVar(&manifestAnnotateOpts.annotations, "annotation", nil, "set an `annotation` for the specified image")
+	flags.BoolVar(&manifestAnnotateOpts.index, "index", false, "set the `annotation` on the image index itself instead of on an instance")
+	flags.StringVar(&manifestAnnotateOpts.artifactType, "artifact-type", "", "set the artifactType of the image index (unsupported by the vendored OCI image-spec version in this build)")
+	flags.StringVar(&manifestAnnotateOpts.subject, "subject", "", "set the subject of the image index (unsupported by the vendored OCI image-spec version in this build)")
 	manifestAnnotateCommand.SetUsageTemplate(UsageTemplate())
 	manifestCommand.AddCommand(manifestAnnotateCommand)
 
@@ -173,12 +249,43 @@ func init() {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return manifestInspectCmd(cmd, args, manifestInspectOpts)
 		},
-		Example: `buildah manifest inspect mylist:v1.11`,
-		Args:    cobra.MinimumNArgs(1),
+		Example: `buildah manifest inspect mylist:v1.11
+  buildah manifest inspect --format '{{range .manifests}}{{.digest}} {{.size}}{{println}}{{end}}' registry.example.com/list:v1.11`,
+		Args: cobra.MinimumNArgs(1),
 	}
 	manifestInspectCommand.SetUsageTemplate(UsageTemplate())
+	inspectFlags := manifestInspectCommand.Flags()
+	inspectFlags.StringVarP(&manifestInspectOpts.format, "format", "f", "", "use `format` as a Go template to format the output")
 	manifestCommand.AddCommand(manifestInspectCommand)
 
+	manifestMergeCommand := &cobra.Command{
+		Use:   "merge",
+		Short: "Merge manifest lists and image indexes",
+		Long:  manifestMergeDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return manifestMergeCmd(cmd, args, manifestMergeOpts)
+		},
+		Example: `buildah manifest merge mylist:v1.11 list1:v1.11 list2:v1.11`,
+		Args:    cobra.MinimumNArgs(2),
+	}
+	manifestMergeCommand.SetUsageTemplate(UsageTemplate())
+	flags = manifestMergeCommand.Flags()
+	flags.StringVar(&manifestMergeOpts.onConflict, "on-conflict", "error", "how to resolve a `policy` conflict when the same platform is present with a different digest in more than one source list: \"error\", \"skip\", or \"replace\"")
+	manifestCommand.AddCommand(manifestMergeCommand)
+
+	manifestDiffCommand := &cobra.Command{
+		Use:   "diff",
+		Short: "Compare the platform instances of two manifest lists or image indexes",
+		Long:  manifestDiffDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return manifestDiffCmd(cmd, args, manifestDiffOpts)
+		},
+		Example: `buildah manifest diff list1:v1.11 list2:v1.11`,
+		Args:    cobra.ExactArgs(2),
+	}
+	manifestDiffCommand.SetUsageTemplate(UsageTemplate())
+	manifestCommand.AddCommand(manifestDiffCommand)
+
 	manifestPushCommand := &cobra.Command{
 		Use:   "push",
 		Short: "Push a manifest list or image index to a registry",
@@ -206,6 +313,8 @@ func init() {
 	}
 	flags.BoolVar(&manifestPushOpts.tlsVerify, "tls-verify", true, "require HTTPS and verify certificates when accessing the registry. TLS verification cannot be used when talking to an insecure registry.")
 	flags.BoolVarP(&manifestPushOpts.quiet, "quiet", "q", false, "don't output progress information when pushing lists")
+	flags.BoolVar(&manifestPushOpts.verify, "verify", false, "after pushing, re-fetch the manifest list and its instances from the destination and confirm that the registry didn't alter them")
+	flags.StringVar(&manifestPushOpts.signBySigstore, "sign-by-sigstore", "", "sign the pushed index and each of its instances using a sigstore parameter `file` (unsupported by the vendored image-copy library in this build)")
 	flags.SetNormalizeFunc(cli.AliasFlags)
 	manifestCommand.AddCommand(manifestPushCommand)
 
@@ -230,6 +339,13 @@ func manifestCreateCmd(c *cobra.Command, args []string, opts manifestCreateOpts)
 	listImageSpec := args[0]
 	imageSpecs := args[1:]
 
+	if opts.artifactType != "" && !opts.artifact {
+		return errors.New("--artifact-type can only be used with --artifact")
+	}
+	if opts.artifact && opts.artifactType == "" {
+		return errors.New("--artifact requires --artifact-type")
+	}
+
 	store, err := getStore(c)
 	if err != nil {
 		return err
@@ -240,13 +356,35 @@ func manifestCreateCmd(c *cobra.Command, args []string, opts manifestCreateOpts)
 		return errors.Wrapf(err, "error building system context")
 	}
 
-	list := manifests.Create()
+	runtime, err := libimage.RuntimeFromStore(store, &libimage.RuntimeOptions{SystemContext: systemContext})
+	if err != nil {
+		return err
+	}
+
+	var list manifests.List
+	existingID := ""
+	if opts.amend {
+		if manifestList, lookupErr := runtime.LookupManifestList(listImageSpec); lookupErr == nil {
+			if _, loaded, loadErr := manifests.LoadFromImage(store, manifestList.ID()); loadErr == nil {
+				list = loaded
+				existingID = manifestList.ID()
+			}
+		}
+	}
+	if list == nil {
+		list = manifests.Create()
+	}
 
 	names, err := util.ExpandNames([]string{listImageSpec}, systemContext, store)
 	if err != nil {
 		return errors.Wrapf(err, "error encountered while expanding image name %q", listImageSpec)
 	}
 
+	imageSpecs, err = expandManifestCreateImageSpecs(runtime, imageSpecs)
+	if err != nil {
+		return err
+	}
+
 	for _, imageSpec := range imageSpecs {
 		ref, err := alltransports.ParseImageName(imageSpec)
 		if err != nil {
@@ -262,13 +400,74 @@ func manifestCreateCmd(c *cobra.Command, args []string, opts manifestCreateOpts)
 		}
 	}
 
-	imageID, err := list.SaveToImage(store, "", names, manifest.DockerV2ListMediaType)
+	if opts.artifact {
+		// The vendored OCI image-spec in this build predates the
+		// "artifactType" index field, so we record the artifact's type as an
+		// index annotation instead.  Instances still have to be images (for
+		// example, ones committed from a scratch container holding the
+		// artifact's files) rather than arbitrary standalone blobs, since
+		// this library's List only knows how to reference image manifests.
+		annotations, err := list.Annotations(nil)
+		if err != nil {
+			return err
+		}
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations["org.opencontainers.artifact.type"] = opts.artifactType
+		if err := list.SetAnnotations(nil, annotations); err != nil {
+			return err
+		}
+	}
+
+	imageID, err := list.SaveToImage(store, existingID, names, manifest.DockerV2ListMediaType)
 	if err == nil {
 		fmt.Printf("%s\n", imageID)
 	}
 	return err
 }
 
+// expandManifestCreateImageSpecs expands any argument that looks like a glob
+// pattern (contains '*', '?', or '[') into the names of all local images
+// which match it, so that a caller can add every image produced by a
+// multi-arch CI build (e.g. "myapp:1.0-*-arch") without listing each
+// architecture-specific tag individually.  Arguments that aren't glob
+// patterns are passed through unchanged.
+func expandManifestCreateImageSpecs(runtime *libimage.Runtime, imageSpecs []string) ([]string, error) {
+	var expanded []string
+	var localNames []string
+	for _, imageSpec := range imageSpecs {
+		if !strings.ContainsAny(imageSpec, "*?[") {
+			expanded = append(expanded, imageSpec)
+			continue
+		}
+		if localNames == nil {
+			images, err := runtime.ListImages(getContext(), nil, nil)
+			if err != nil {
+				return nil, errors.Wrap(err, "error listing local images to expand manifest create pattern")
+			}
+			for _, image := range images {
+				localNames = append(localNames, image.Names()...)
+			}
+		}
+		matched := false
+		for _, name := range localNames {
+			ok, err := filepath.Match(imageSpec, name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid pattern %q", imageSpec)
+			}
+			if ok {
+				expanded = append(expanded, name)
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, errors.Errorf("no local images matched pattern %q", imageSpec)
+		}
+	}
+	return expanded, nil
+}
+
 func manifestAddCmd(c *cobra.Command, args []string, opts manifestAddOpts) error {
 	if err := auth.CheckAuthFile(opts.authfile); err != nil {
 		return err
@@ -325,50 +524,58 @@ func manifestAddCmd(c *cobra.Command, args []string, opts manifestAddOpts) error
 		}
 	}
 
-	digest, err := list.Add(getContext(), systemContext, ref, opts.all)
+	newInstanceDigest, err := list.Add(getContext(), systemContext, ref, opts.all)
 	if err != nil {
 		var storeErr error
 		// check if the local image exists
 		if ref, _, storeErr = util.FindImage(store, "", systemContext, imageSpec); storeErr != nil {
 			return err
 		}
-		digest, storeErr = list.Add(getContext(), systemContext, ref, opts.all)
+		newInstanceDigest, storeErr = list.Add(getContext(), systemContext, ref, opts.all)
 		if storeErr != nil {
 			return err
 		}
 	}
 
 	if opts.os != "" {
-		if err := list.SetOS(digest, opts.os); err != nil {
+		if err := list.SetOS(newInstanceDigest, opts.os); err != nil {
 			return err
 		}
 	}
-	if opts.osVersion != "" {
-		if err := list.SetOSVersion(digest, opts.osVersion); err != nil {
+	osVersion := opts.osVersion
+	if osVersion == "" {
+		// OS version isn't part of the OCI image config, so the OS/Arch
+		// auto-detection that list.Add() already does for us doesn't cover
+		// it; look it up ourselves from the Docker-style image config so
+		// that Windows images end up in the list with a version attached.
+		osVersion = manifestAddDetectOSVersion(getContext(), systemContext, ref)
+	}
+	if osVersion != "" {
+		if err := list.SetOSVersion(newInstanceDigest, osVersion); err != nil {
 			return err
 		}
 	}
 	if len(opts.osFeatures) != 0 {
-		if err := list.SetOSFeatures(digest, opts.osFeatures); err != nil {
+		if err := list.SetOSFeatures(newInstanceDigest, opts.osFeatures); err != nil {
 			return err
 		}
 	}
 	if opts.arch != "" {
-		if err := list.SetArchitecture(digest, opts.arch); err != nil {
+		if err := list.SetArchitecture(newInstanceDigest, opts.arch); err != nil {
 			return err
 		}
 	}
 	if opts.variant != "" {
-		if err := list.SetVariant(digest, opts.variant); err != nil {
+		if err := list.SetVariant(newInstanceDigest, opts.variant); err != nil {
 			return err
 		}
 	}
 	if len(opts.features) != 0 {
-		if err := list.SetFeatures(digest, opts.features); err != nil {
+		if err := list.SetFeatures(newInstanceDigest, opts.features); err != nil {
 			return err
 		}
 	}
-	if len(opts.annotations) != 0 {
+	if len(opts.annotations) != 0 || opts.attestationFor != "" {
 		annotations := make(map[string]string)
 		for _, annotationSpec := range opts.annotations {
 			spec := strings.SplitN(annotationSpec, "=", 2)
@@ -377,30 +584,81 @@ func manifestAddCmd(c *cobra.Command, args []string, opts manifestAddOpts) error
 			}
 			annotations[spec[0]] = spec[1]
 		}
-		if err := list.SetAnnotations(&digest, annotations); err != nil {
+		if opts.attestationFor != "" {
+			subjectDigest, err := digest.Parse(opts.attestationFor)
+			if err != nil {
+				return errors.Errorf(`Invalid instance digest for --attestation-for "%s": %v`, opts.attestationFor, err)
+			}
+			// These are the annotations that BuildKit attaches to
+			// attestation manifests, and that registries and "docker
+			// buildx imagetools" already know to look for.
+			annotations["vnd.docker.reference.type"] = "attestation-manifest"
+			annotations["vnd.docker.reference.digest"] = subjectDigest.String()
+		}
+		if err := list.SetAnnotations(&newInstanceDigest, annotations); err != nil {
 			return err
 		}
 	}
 
 	updatedListID, err := list.SaveToImage(store, manifestList.ID(), nil, "")
 	if err == nil {
-		fmt.Printf("%s: %s\n", updatedListID, digest.String())
+		fmt.Printf("%s: %s\n", updatedListID, newInstanceDigest.String())
 	}
 
 	return err
 }
 
+// manifestAddDetectOSVersion tries to read the "os.version" field out of the
+// Docker-style image configuration for the image that ref refers to.  It's
+// best-effort: any error, or an image which doesn't set the field, just
+// results in an empty string, so that callers can fall back to leaving the
+// value unset rather than failing the add.
+func manifestAddDetectOSVersion(ctx context.Context, sys *types.SystemContext, ref types.ImageReference) string {
+	src, err := ref.NewImageSource(ctx, sys)
+	if err != nil {
+		return ""
+	}
+	defer src.Close()
+	img, err := image.FromSource(ctx, sys, src)
+	if err != nil {
+		return ""
+	}
+	defer img.Close()
+	configBlob, err := img.ConfigBlob(ctx)
+	if err != nil || len(configBlob) == 0 {
+		return ""
+	}
+	config := struct {
+		OSVersion string `json:"os.version,omitempty"`
+	}{}
+	if err := json.Unmarshal(configBlob, &config); err != nil {
+		return ""
+	}
+	return config.OSVersion
+}
+
 func manifestRemoveCmd(c *cobra.Command, args []string, opts manifestRemoveOpts) error {
 	listImageSpec := ""
 	var instanceDigest digest.Digest
 	switch len(args) {
-	case 0, 1:
-		return errors.New("At least a list image and one or more instance digests must be specified")
+	case 0:
+		return errors.New("At least a list image must be specified")
+	case 1:
+		listImageSpec = args[0]
+		if listImageSpec == "" {
+			return errors.Errorf(`Invalid image name "%s"`, args[0])
+		}
+		if opts.platform == "" {
+			return errors.New("Either an instance digest or --platform must be specified")
+		}
 	case 2:
 		listImageSpec = args[0]
 		if listImageSpec == "" {
 			return errors.Errorf(`Invalid image name "%s"`, args[0])
 		}
+		if opts.platform != "" {
+			return errors.New("--platform and an instance digest cannot both be specified")
+		}
 		instanceSpec := args[1]
 		if instanceSpec == "" {
 			return errors.Errorf(`Invalid instance "%s"`, args[1])
@@ -433,6 +691,13 @@ func manifestRemoveCmd(c *cobra.Command, args []string, opts manifestRemoveOpts)
 		return err
 	}
 
+	if opts.platform != "" {
+		instanceDigest, err = manifestFindInstanceByPlatform(store, manifestList.ID(), opts.platform)
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := manifestList.RemoveInstance(instanceDigest); err != nil {
 		return err
 	}
@@ -478,7 +743,161 @@ func manifestRmCmd(c *cobra.Command, args []string) error {
 	return multiE.ErrorOrNil()
 }
 
+// manifestFindInstanceByPlatform looks through the named manifest list for
+// the single instance matching the given "os/arch[/variant]" platform
+// selector, returning an error if none or more than one instance matches.
+func manifestFindInstanceByPlatform(store storage.Store, listImageID, platform string) (digest.Digest, error) {
+	wantOS, wantArch, wantVariant, err := parse.Platform(platform)
+	if err != nil {
+		return "", err
+	}
+	_, list, err := manifests.LoadFromImage(store, listImageID)
+	if err != nil {
+		return "", err
+	}
+	var matches []digest.Digest
+	for _, instanceDigest := range list.Instances() {
+		os, err := list.OS(instanceDigest)
+		if err != nil || os != wantOS {
+			continue
+		}
+		arch, err := list.Architecture(instanceDigest)
+		if err != nil || arch != wantArch {
+			continue
+		}
+		if wantVariant != "" {
+			variant, err := list.Variant(instanceDigest)
+			if err != nil || variant != wantVariant {
+				continue
+			}
+		}
+		matches = append(matches, instanceDigest)
+	}
+	switch len(matches) {
+	case 0:
+		return "", errors.Errorf("no instance matching platform %q found in manifest list", platform)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", errors.Errorf("more than one instance matching platform %q found in manifest list", platform)
+	}
+}
+
+// manifestListHasPlatform returns true if "list" contains an instance whose
+// os/arch/variant matches "platform" (in "os/arch[/variant]" form).
+func manifestListHasPlatform(list manifests.List, platform string) (bool, error) {
+	wantOS, wantArch, wantVariant, err := parse.Platform(platform)
+	if err != nil {
+		return false, err
+	}
+	for _, instanceDigest := range list.Instances() {
+		os, err := list.OS(instanceDigest)
+		if err != nil || os != wantOS {
+			continue
+		}
+		arch, err := list.Architecture(instanceDigest)
+		if err != nil || arch != wantArch {
+			continue
+		}
+		if wantVariant != "" {
+			variant, err := list.Variant(instanceDigest)
+			if err != nil || variant != wantVariant {
+				continue
+			}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// manifestExistsCmd reports, via its exit code alone, whether a manifest
+// list (and, if --platform was given, a matching instance within it)
+// exists, so that it can be used for scripting without parsing output.
+func manifestExistsCmd(c *cobra.Command, args []string, opts manifestExistsOpts) error {
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+
+	runtime, err := libimage.RuntimeFromStore(store, &libimage.RuntimeOptions{SystemContext: systemContext})
+	if err != nil {
+		return err
+	}
+
+	manifestList, err := runtime.LookupManifestList(args[0])
+	if err != nil {
+		os.Exit(1)
+	}
+
+	if opts.platform != "" {
+		_, list, err := manifests.LoadFromImage(store, manifestList.ID())
+		if err != nil {
+			os.Exit(1)
+		}
+		found, err := manifestListHasPlatform(list, opts.platform)
+		if err != nil {
+			return err
+		}
+		if !found {
+			os.Exit(1)
+		}
+	}
+
+	return nil
+}
+
+func manifestPruneCmd(c *cobra.Command, args []string, opts manifestPruneOpts) error {
+	listImageSpec := args[0]
+	if listImageSpec == "" {
+		return errors.Errorf(`Invalid image name "%s"`, args[0])
+	}
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+
+	runtime, err := libimage.RuntimeFromStore(store, &libimage.RuntimeOptions{SystemContext: systemContext})
+	if err != nil {
+		return err
+	}
+	manifestList, err := runtime.LookupManifestList(listImageSpec)
+	if err != nil {
+		return err
+	}
+
+	_, list, err := manifests.LoadFromImage(store, manifestList.ID())
+	if err != nil {
+		return err
+	}
+
+	for _, instanceDigest := range list.Instances() {
+		if _, err := store.Image(instanceDigest.String()); err != nil {
+			if err := manifestList.RemoveInstance(instanceDigest); err != nil {
+				return errors.Wrapf(err, "error removing instance %s from manifest list", instanceDigest)
+			}
+			fmt.Printf("%s: %s\n", manifestList.ID(), instanceDigest.String())
+		}
+	}
+
+	return nil
+}
+
 func manifestAnnotateCmd(c *cobra.Command, args []string, opts manifestAnnotateOpts) error {
+	if opts.artifactType != "" || opts.subject != "" {
+		return errors.New("--artifact-type and --subject are not supported: the vendored OCI image-spec version in this build predates the image index fields they require")
+	}
+
 	listImageSpec := ""
 	imageSpec := ""
 	switch len(args) {
@@ -489,6 +908,9 @@ func manifestAnnotateCmd(c *cobra.Command, args []string, opts manifestAnnotateO
 		if listImageSpec == "" {
 			return errors.Errorf(`Invalid image name "%s"`, args[0])
 		}
+		if !opts.index {
+			return errors.New("an image to annotate must be specified unless --index is set")
+		}
 	case 2:
 		listImageSpec = args[0]
 		if listImageSpec == "" {
@@ -498,6 +920,9 @@ func manifestAnnotateCmd(c *cobra.Command, args []string, opts manifestAnnotateO
 		if imageSpec == "" {
 			return errors.Errorf(`Invalid image name "%s"`, args[1])
 		}
+		if opts.index {
+			return errors.New("--index annotates the image index itself and does not take an image argument")
+		}
 	default:
 		return errors.New("At least two arguments are necessary: list and image to add to list")
 	}
@@ -526,56 +951,60 @@ func manifestAnnotateCmd(c *cobra.Command, args []string, opts manifestAnnotateO
 		return err
 	}
 
-	digest, err := digest.Parse(imageSpec)
-	if err != nil {
-		ctx := getContext()
-		ref, _, err := util.FindImage(store, "", systemContext, imageSpec)
-		if err != nil {
-			return err
-		}
-		img, err := ref.NewImageSource(ctx, systemContext)
-		if err != nil {
-			return err
-		}
-		defer img.Close()
-		manifestBytes, _, err := img.GetManifest(ctx, nil)
-		if err != nil {
-			return err
-		}
-		digest, err = manifest.Digest(manifestBytes)
+	var instanceDigest *digest.Digest
+	if !opts.index {
+		parsedDigest, err := digest.Parse(imageSpec)
 		if err != nil {
-			return err
+			ctx := getContext()
+			ref, _, err := util.FindImage(store, "", systemContext, imageSpec)
+			if err != nil {
+				return err
+			}
+			img, err := ref.NewImageSource(ctx, systemContext)
+			if err != nil {
+				return err
+			}
+			defer img.Close()
+			manifestBytes, _, err := img.GetManifest(ctx, nil)
+			if err != nil {
+				return err
+			}
+			parsedDigest, err = manifest.Digest(manifestBytes)
+			if err != nil {
+				return err
+			}
 		}
-	}
+		instanceDigest = &parsedDigest
 
-	if opts.os != "" {
-		if err := list.SetOS(digest, opts.os); err != nil {
-			return err
+		if opts.os != "" {
+			if err := list.SetOS(*instanceDigest, opts.os); err != nil {
+				return err
+			}
 		}
-	}
-	if opts.osVersion != "" {
-		if err := list.SetOSVersion(digest, opts.osVersion); err != nil {
-			return err
+		if opts.osVersion != "" {
+			if err := list.SetOSVersion(*instanceDigest, opts.osVersion); err != nil {
+				return err
+			}
 		}
-	}
-	if len(opts.osFeatures) != 0 {
-		if err := list.SetOSFeatures(digest, opts.osFeatures); err != nil {
-			return err
+		if len(opts.osFeatures) != 0 {
+			if err := list.SetOSFeatures(*instanceDigest, opts.osFeatures); err != nil {
+				return err
+			}
 		}
-	}
-	if opts.arch != "" {
-		if err := list.SetArchitecture(digest, opts.arch); err != nil {
-			return err
+		if opts.arch != "" {
+			if err := list.SetArchitecture(*instanceDigest, opts.arch); err != nil {
+				return err
+			}
 		}
-	}
-	if opts.variant != "" {
-		if err := list.SetVariant(digest, opts.variant); err != nil {
-			return err
+		if opts.variant != "" {
+			if err := list.SetVariant(*instanceDigest, opts.variant); err != nil {
+				return err
+			}
 		}
-	}
-	if len(opts.features) != 0 {
-		if err := list.SetFeatures(digest, opts.features); err != nil {
-			return err
+		if len(opts.features) != 0 {
+			if err := list.SetFeatures(*instanceDigest, opts.features); err != nil {
+				return err
+			}
 		}
 	}
 	if len(opts.annotations) != 0 {
@@ -587,14 +1016,18 @@ func manifestAnnotateCmd(c *cobra.Command, args []string, opts manifestAnnotateO
 			}
 			annotations[spec[0]] = spec[1]
 		}
-		if err := list.SetAnnotations(&digest, annotations); err != nil {
+		if err := list.SetAnnotations(instanceDigest, annotations); err != nil {
 			return err
 		}
 	}
 
 	updatedListID, err := list.SaveToImage(store, manifestList.ID(), nil, "")
 	if err == nil {
-		fmt.Printf("%s: %s\n", updatedListID, digest.String())
+		if instanceDigest != nil {
+			fmt.Printf("%s: %s\n", updatedListID, instanceDigest.String())
+		} else {
+			fmt.Printf("%s\n", updatedListID)
+		}
 	}
 
 	return nil
@@ -624,16 +1057,24 @@ func manifestInspectCmd(c *cobra.Command, args []string, opts manifestInspectOpt
 		return errors.Wrapf(err, "error building system context")
 	}
 
-	return manifestInspect(getContext(), store, systemContext, imageSpec)
+	return manifestInspectFormat(getContext(), store, systemContext, imageSpec, opts.format)
 }
 
 func manifestInspect(ctx context.Context, store storage.Store, systemContext *types.SystemContext, imageSpec string) error {
+	return manifestInspectFormat(ctx, store, systemContext, imageSpec, "")
+}
+
+func manifestInspectFormat(ctx context.Context, store storage.Store, systemContext *types.SystemContext, imageSpec, format string) error {
 	runtime, err := libimage.RuntimeFromStore(store, &libimage.RuntimeOptions{SystemContext: systemContext})
 	if err != nil {
 		return err
 	}
 
 	printManifest := func(manifest []byte) error {
+		if format != "" {
+			return formatManifestOutput(manifest, format)
+		}
+
 		var b bytes.Buffer
 		err = json.Indent(&b, manifest, "", "    ")
 		if err != nil {
@@ -728,7 +1169,240 @@ func manifestInspect(ctx context.Context, store storage.Store, systemContext *ty
 	return printManifest(result)
 }
 
+func formatManifestOutput(rawManifest []byte, format string) error {
+	if matched, err := regexp.MatchString("{{.*}}", format); err != nil {
+		return errors.Wrapf(err, "error validating format provided: %s", format)
+	} else if !matched {
+		return errors.Errorf("error invalid format provided: %s", format)
+	}
+	var out interface{}
+	if err := json.Unmarshal(rawManifest, &out); err != nil {
+		return errors.Wrapf(err, "error parsing manifest for formatting")
+	}
+	t, err := template.New("format").Parse(format)
+	if err != nil {
+		return errors.Wrapf(err, "Template parsing error")
+	}
+	if err := t.Execute(os.Stdout, out); err != nil {
+		return err
+	}
+	if terminal.IsTerminal(int(os.Stdout.Fd())) {
+		fmt.Println()
+	}
+	return nil
+}
+
+// platformKey builds the map key used to identify the platform instance a
+// manifest list entry was built for, so that instances from different lists
+// can be compared or merged without regard to the digests naming them.
+func platformKey(os, arch, variant string) string {
+	if variant == "" {
+		return fmt.Sprintf("%s/%s", os, arch)
+	}
+	return fmt.Sprintf("%s/%s/%s", os, arch, variant)
+}
+
+// manifestPlatformKey returns the platformKey for an instance already
+// present in a manifests.List, using its recorded OS, architecture, and
+// variant.
+func manifestPlatformKey(list manifests.List, instanceDigest digest.Digest) (string, error) {
+	os, err := list.OS(instanceDigest)
+	if err != nil {
+		return "", err
+	}
+	arch, err := list.Architecture(instanceDigest)
+	if err != nil {
+		return "", err
+	}
+	variant, err := list.Variant(instanceDigest)
+	if err != nil {
+		return "", err
+	}
+	return platformKey(os, arch, variant), nil
+}
+
+// loadManifestListByImageSpec resolves the given name to a manifest list
+// image in local storage and loads it, for commands like merge and diff
+// that only ever operate on lists which already exist locally.
+func loadManifestListByImageSpec(runtime *libimage.Runtime, store storage.Store, imageSpec string) (manifests.List, error) {
+	manifestList, err := runtime.LookupManifestList(imageSpec)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error locating manifest list %q", imageSpec)
+	}
+	_, list, err := manifests.LoadFromImage(store, manifestList.ID())
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func manifestMergeCmd(c *cobra.Command, args []string, opts manifestMergeOpts) error {
+	switch opts.onConflict {
+	case "error", "skip", "replace":
+	default:
+		return errors.Errorf(`invalid --on-conflict value %q: must be "error", "skip", or "replace"`, opts.onConflict)
+	}
+
+	listImageSpec := args[0]
+	if listImageSpec == "" {
+		return errors.Errorf(`Invalid image name "%s"`, args[0])
+	}
+	sourceImageSpecs := args[1:]
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+
+	runtime, err := libimage.RuntimeFromStore(store, &libimage.RuntimeOptions{SystemContext: systemContext})
+	if err != nil {
+		return err
+	}
+
+	var destList manifests.List
+	existingID := ""
+	if manifestList, lookupErr := runtime.LookupManifestList(listImageSpec); lookupErr == nil {
+		if _, loaded, loadErr := manifests.LoadFromImage(store, manifestList.ID()); loadErr == nil {
+			destList = loaded
+			existingID = manifestList.ID()
+		}
+	}
+	if destList == nil {
+		destList = manifests.Create()
+	}
+
+	names, err := util.ExpandNames([]string{listImageSpec}, systemContext, store)
+	if err != nil {
+		return errors.Wrapf(err, "error encountered while expanding image name %q", listImageSpec)
+	}
+
+	destPlatforms := make(map[string]digest.Digest)
+	for _, instanceDigest := range destList.Instances() {
+		key, err := manifestPlatformKey(destList, instanceDigest)
+		if err != nil {
+			return err
+		}
+		destPlatforms[key] = instanceDigest
+	}
+
+	for _, sourceImageSpec := range sourceImageSpecs {
+		sourceList, err := loadManifestListByImageSpec(runtime, store, sourceImageSpec)
+		if err != nil {
+			return err
+		}
+		for _, instance := range sourceList.OCIv1().Manifests {
+			if instance.Platform == nil {
+				continue
+			}
+			key := platformKey(instance.Platform.OS, instance.Platform.Architecture, instance.Platform.Variant)
+			if existingDigest, conflict := destPlatforms[key]; conflict && existingDigest != instance.Digest {
+				switch opts.onConflict {
+				case "error":
+					return errors.Errorf("platform %s is provided by both %s (%s) and %s (%s): use --on-conflict to resolve", key, listImageSpec, existingDigest, sourceImageSpec, instance.Digest)
+				case "skip":
+					continue
+				case "replace":
+					if err := destList.Remove(existingDigest); err != nil && !os.IsNotExist(errors.Cause(err)) {
+						return err
+					}
+				}
+			}
+			if err := destList.AddInstance(instance.Digest, instance.Size, instance.MediaType, instance.Platform.OS, instance.Platform.Architecture, instance.Platform.OSVersion, instance.Platform.OSFeatures, instance.Platform.Variant, nil, nil); err != nil {
+				return errors.Wrapf(err, "error adding instance %s from %q to %q", instance.Digest, sourceImageSpec, listImageSpec)
+			}
+			if len(instance.Annotations) != 0 {
+				if err := destList.SetAnnotations(&instance.Digest, instance.Annotations); err != nil {
+					return err
+				}
+			}
+			destPlatforms[key] = instance.Digest
+		}
+	}
+
+	imageID, err := destList.SaveToImage(store, existingID, names, manifest.DockerV2ListMediaType)
+	if err == nil {
+		fmt.Printf("%s\n", imageID)
+	}
+	return err
+}
+
+func manifestDiffCmd(c *cobra.Command, args []string, opts manifestDiffOpts) error {
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+
+	runtime, err := libimage.RuntimeFromStore(store, &libimage.RuntimeOptions{SystemContext: systemContext})
+	if err != nil {
+		return err
+	}
+
+	list1, err := loadManifestListByImageSpec(runtime, store, args[0])
+	if err != nil {
+		return err
+	}
+	list2, err := loadManifestListByImageSpec(runtime, store, args[1])
+	if err != nil {
+		return err
+	}
+
+	instances1 := make(map[string]imgspecv1.Descriptor)
+	for _, instance := range list1.OCIv1().Manifests {
+		if instance.Platform == nil {
+			continue
+		}
+		instances1[platformKey(instance.Platform.OS, instance.Platform.Architecture, instance.Platform.Variant)] = instance
+	}
+	instances2 := make(map[string]imgspecv1.Descriptor)
+	for _, instance := range list2.OCIv1().Manifests {
+		if instance.Platform == nil {
+			continue
+		}
+		instances2[platformKey(instance.Platform.OS, instance.Platform.Architecture, instance.Platform.Variant)] = instance
+	}
+
+	var keys []string
+	for key := range instances1 {
+		keys = append(keys, key)
+	}
+	for key := range instances2 {
+		if _, ok := instances1[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		instance1, ok1 := instances1[key]
+		instance2, ok2 := instances2[key]
+		switch {
+		case ok1 && !ok2:
+			fmt.Printf("- %s %s\n", key, instance1.Digest)
+		case !ok1 && ok2:
+			fmt.Printf("+ %s %s\n", key, instance2.Digest)
+		case instance1.Digest != instance2.Digest:
+			fmt.Printf("~ %s %s -> %s\n", key, instance1.Digest, instance2.Digest)
+		}
+	}
+
+	return nil
+}
+
 func manifestPushCmd(c *cobra.Command, args []string, opts pushOptions) error {
+	if opts.signBySigstore != "" {
+		return errors.New("--sign-by-sigstore is not supported: the vendored containers/image copy library in this build predates sigstore/cosign signing support")
+	}
+
 	if err := auth.CheckAuthFile(opts.authfile); err != nil {
 		return err
 	}
@@ -816,6 +1490,10 @@ func manifestPush(systemContext *types.SystemContext, store storage.Store, listI
 
 	_, digest, err := list.Push(getContext(), dest, options)
 
+	if err == nil && opts.verify {
+		err = verifyManifestPush(getContext(), systemContext, dest, digest)
+	}
+
 	if err == nil && opts.rm {
 		_, err = store.DeleteImage(manifestList.ID(), true)
 	}
@@ -828,3 +1506,72 @@ func manifestPush(systemContext *types.SystemContext, store storage.Store, listI
 
 	return err
 }
+
+// verifyManifestPush re-fetches the manifest list and each of its instances
+// from dest and confirms that their digests, and the existence of the blobs
+// each instance's manifest references, match what was just pushed, in case
+// the registry rewrote anything along the way.
+func verifyManifestPush(ctx context.Context, systemContext *types.SystemContext, dest types.ImageReference, pushedDigest digest.Digest) error {
+	src, err := dest.NewImageSource(ctx, systemContext)
+	if err != nil {
+		return errors.Wrapf(err, "error re-reading manifest list from %q to verify the push", transports.ImageName(dest))
+	}
+	defer src.Close()
+
+	rawList, listType, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return errors.Wrapf(err, "error fetching manifest list from %q to verify the push", transports.ImageName(dest))
+	}
+	if fetchedDigest, err := manifest.Digest(rawList); err != nil {
+		return errors.Wrapf(err, "error computing digest of manifest list fetched from %q", transports.ImageName(dest))
+	} else if fetchedDigest != pushedDigest {
+		return errors.Errorf("verification failed: manifest list at %q has digest %s, expected %s: registry may have altered it", transports.ImageName(dest), fetchedDigest, pushedDigest)
+	}
+
+	parsedList, err := manifest.ListFromBlob(rawList, listType)
+	if err != nil {
+		// Not actually a list, so there are no per-platform instances to check.
+		return nil
+	}
+
+	for _, instanceDigest := range parsedList.Instances() {
+		instanceDigest := instanceDigest
+		rawInstance, instanceType, err := src.GetManifest(ctx, &instanceDigest)
+		if err != nil {
+			return errors.Wrapf(err, "error fetching instance %s from %q to verify the push", instanceDigest, transports.ImageName(dest))
+		}
+		fetchedInstanceDigest, err := manifest.Digest(rawInstance)
+		if err != nil {
+			return errors.Wrapf(err, "error computing digest of instance %s fetched from %q", instanceDigest, transports.ImageName(dest))
+		}
+		if fetchedInstanceDigest != instanceDigest {
+			return errors.Errorf("verification failed: instance %s at %q was fetched back with digest %s: registry may have altered it", instanceDigest, transports.ImageName(dest), fetchedInstanceDigest)
+		}
+
+		instanceManifest, err := manifest.FromBlob(rawInstance, instanceType)
+		if err != nil {
+			return errors.Wrapf(err, "error parsing instance %s fetched from %q", instanceDigest, transports.ImageName(dest))
+		}
+		blobInfos := append([]types.BlobInfo{instanceManifest.ConfigInfo()}, blobInfosFromLayerInfos(instanceManifest.LayerInfos())...)
+		for _, blobInfo := range blobInfos {
+			if blobInfo.Digest == "" {
+				continue
+			}
+			reader, _, err := src.GetBlob(ctx, blobInfo, nonecache.NoCache)
+			if err != nil {
+				return errors.Wrapf(err, "verification failed: instance %s at %q is missing blob %s", instanceDigest, transports.ImageName(dest), blobInfo.Digest)
+			}
+			reader.Close()
+		}
+	}
+
+	return nil
+}
+
+func blobInfosFromLayerInfos(layerInfos []manifest.LayerInfo) []types.BlobInfo {
+	blobInfos := make([]types.BlobInfo, len(layerInfos))
+	for i, layerInfo := range layerInfos {
+		blobInfos[i] = layerInfo.BlobInfo
+	}
+	return blobInfos
+}
@@ -17,7 +17,9 @@ type jsonMount struct {
 }
 
 type mountOptions struct {
-	json bool
+	json     bool
+	all      bool
+	readOnly bool
 }
 
 func init() {
@@ -42,6 +44,7 @@ func init() {
 		Example: `buildah mount
   buildah mount containerID
   buildah mount containerID1 containerID2
+  buildah mount --all
 
   In rootless mode you must use buildah unshare first.
   buildah unshare
@@ -53,6 +56,8 @@ func init() {
 	flags := mountCommand.Flags()
 	flags.SetInterspersed(false)
 	flags.BoolVar(&opts.json, "json", false, "output in JSON format")
+	flags.BoolVarP(&opts.all, "all", "a", false, "mount all of the currently existing working containers")
+	flags.BoolVar(&opts.readOnly, "read-only", false, "mount the root filesystem without marking the container as in use for writes")
 	flags.BoolVar(&noTruncate, "notruncate", false, "do not truncate output")
 	rootCmd.AddCommand(mountCommand)
 	if err := flags.MarkHidden("notruncate"); err != nil {
@@ -65,6 +70,12 @@ func mountCmd(c *cobra.Command, args []string, opts mountOptions) error {
 	if err := buildahcli.VerifyFlagsArgsOrder(args); err != nil {
 		return err
 	}
+	if opts.readOnly {
+		return errors.Errorf("--read-only is not supported: the underlying container storage does not expose a way to mount a working container's root filesystem without marking it in use for writes")
+	}
+	if opts.all && len(args) > 0 {
+		return errors.Errorf("when using the --all switch, you may not pass any container IDs")
+	}
 
 	store, err := getStore(c)
 	if err != nil {
@@ -72,7 +83,27 @@ func mountCmd(c *cobra.Command, args []string, opts mountOptions) error {
 	}
 	var jsonMounts []jsonMount
 	var lastError error
-	if len(args) > 0 {
+	if opts.all {
+		builders, err := openBuilders(store)
+		if err != nil {
+			return errors.Wrapf(err, "error reading build containers")
+		}
+		for _, builder := range builders {
+			mountPoint, err := builder.Mount(builder.MountLabel)
+			if err != nil {
+				if lastError != nil {
+					fmt.Fprintln(os.Stderr, lastError)
+				}
+				lastError = errors.Wrapf(err, "error mounting container %q", builder.Container)
+				continue
+			}
+			if opts.json {
+				jsonMounts = append(jsonMounts, jsonMount{Container: builder.Container, MountPoint: mountPoint})
+				continue
+			}
+			fmt.Printf("%s %s\n", builder.Container, mountPoint)
+		}
+	} else if len(args) > 0 {
 		// Do not allow to mount a graphdriver that is not vfs if we are creating the userns as part
 		// of the mount command.
 		// Differently, allow the mount if we are already in a userns, as the mount point will still
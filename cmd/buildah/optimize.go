@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/containers/buildah"
+	"github.com/containers/buildah/define"
+	"github.com/containers/buildah/pkg/parse"
+	"github.com/containers/common/libimage"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type optimizeInputOptions struct {
+	quiet bool
+	zstd  bool
+}
+
+func init() {
+	var (
+		opts                optimizeInputOptions
+		optimizeDescription = "\n  Rewrites an image as a single, freshly-compressed layer to improve pull\n  performance, and reports the size of the image before and after."
+	)
+	optimizeCommand := &cobra.Command{
+		Use:   "optimize",
+		Short: "Optimize an image for faster pulls",
+		Long:  optimizeDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return optimizeCmd(cmd, args, opts)
+		},
+		Args: cobra.RangeArgs(1, 2),
+		Example: `buildah optimize imageName
+  buildah optimize imageName newImageName
+  buildah optimize --zstd imageName newImageName`,
+	}
+	optimizeCommand.SetUsageTemplate(UsageTemplate())
+	flags := optimizeCommand.Flags()
+	flags.SetInterspersed(false)
+	flags.BoolVarP(&opts.quiet, "quiet", "q", false, "don't output progress information when writing the optimized image")
+	flags.BoolVar(&opts.zstd, "zstd", false, "compress the optimized image's layer with zstd instead of gzip")
+
+	rootCmd.AddCommand(optimizeCommand)
+}
+
+func optimizeCmd(c *cobra.Command, args []string, iopts optimizeInputOptions) error {
+	name := args[0]
+	dest := ""
+	if len(args) > 1 {
+		dest = args[1]
+	}
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+
+	ctx := getContext()
+
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+
+	imageRuntime, err := libimage.RuntimeFromStore(store, &libimage.RuntimeOptions{SystemContext: systemContext})
+	if err != nil {
+		return err
+	}
+	before, _, err := imageRuntime.LookupImage(name, nil)
+	if err != nil {
+		return errors.Wrapf(err, "error looking up image %q", name)
+	}
+	beforeSize, err := before.Size()
+	if err != nil {
+		return errors.Wrapf(err, "error determining size of image %q", name)
+	}
+
+	builder, err := buildah.NewBuilder(ctx, store, buildah.BuilderOptions{
+		FromImage:     name,
+		PullPolicy:    define.PullIfMissing,
+		SystemContext: systemContext,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error reading image %q", name)
+	}
+
+	compress := define.Gzip
+	if iopts.zstd {
+		compress = define.Zstd
+	}
+
+	options := buildah.CommitOptions{
+		Compression:   compress,
+		Squash:        true,
+		SystemContext: systemContext,
+	}
+	if !iopts.quiet {
+		options.ReportWriter = os.Stderr
+	}
+
+	id, _, _, err := builder.Commit(ctx, nil, options)
+	deleteErr := builder.Delete()
+	if err != nil {
+		return errors.Wrapf(err, "error optimizing image %q", name)
+	}
+	if deleteErr != nil {
+		logrus.Errorf("error removing temporary container %q: %v", builder.ContainerID, deleteErr)
+	}
+
+	after, _, err := imageRuntime.LookupImage(id, nil)
+	if err != nil {
+		return errors.Wrapf(err, "error looking up optimized image %q", id)
+	}
+	afterSize, err := after.Size()
+	if err != nil {
+		return errors.Wrapf(err, "error determining size of optimized image %q", id)
+	}
+
+	if dest != "" {
+		if err := after.Tag(dest); err != nil {
+			return errors.Wrapf(err, "error tagging optimized image as %q", dest)
+		}
+	}
+
+	fmt.Printf("%s\n", id)
+	fmt.Fprintf(os.Stderr, "size before: %s, size after: %s\n", formattedSize(beforeSize), formattedSize(afterSize))
+	return nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/containers/buildah/pkg/parse"
+	"github.com/containers/common/libimage"
+	units "github.com/docker/go-units"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type pruneOptions struct {
+	containers bool
+	images     bool
+	buildCache bool
+	all        bool
+	force      bool
+	filter     []string
+}
+
+func init() {
+	var (
+		pruneDescription = "\n  Removes unused working containers, dangling images, and intermediate build-cache images, reporting how much space was reclaimed."
+		opts             pruneOptions
+	)
+	pruneCommand := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove working containers and unused images",
+		Long:  pruneDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return pruneCmd(cmd, args, opts)
+		},
+		Example: `buildah prune
+  buildah prune --all --force
+  buildah prune --images --filter until=48h`,
+		Args: cobra.NoArgs,
+	}
+	pruneCommand.SetUsageTemplate(UsageTemplate())
+
+	flags := pruneCommand.Flags()
+	flags.BoolVar(&opts.containers, "containers", false, "remove working containers only")
+	flags.BoolVar(&opts.images, "images", false, "remove dangling images only")
+	flags.BoolVar(&opts.buildCache, "build-cache", false, "remove intermediate images left behind by builds using --layers only")
+	flags.BoolVarP(&opts.all, "all", "a", false, "also remove images which aren't dangling or intermediate, but also aren't in use by any container or tag")
+	flags.BoolVarP(&opts.force, "force", "f", false, "don't stop to ask for confirmation, and remove images that are still in use by containers")
+	flags.StringArrayVar(&opts.filter, "filter", nil, "only prune images matching the given criteria (currently supports \"until=<duration>\", e.g. \"until=24h\")")
+
+	rootCmd.AddCommand(pruneCommand)
+}
+
+func pruneCmd(c *cobra.Command, args []string, iopts pruneOptions) error {
+	pruneContainers := iopts.containers || iopts.all || (!iopts.images && !iopts.buildCache)
+	pruneDanglingImages := iopts.images || iopts.all || (!iopts.containers && !iopts.buildCache)
+	pruneBuildCache := iopts.buildCache || iopts.all || (!iopts.containers && !iopts.images)
+
+	until, err := parsePruneUntilFilter(iopts.filter)
+	if err != nil {
+		return err
+	}
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+
+	var multiE *multierror.Error
+	var reclaimed int64
+	var containersRemoved int
+
+	if pruneContainers {
+		builders, err := openBuilders(store)
+		if err != nil {
+			return errors.Wrapf(err, "error reading build containers")
+		}
+		for _, builder := range builders {
+			if !until.IsZero() && builder.FromImageID != "" {
+				// Leave containers based on images that are still fresh alone.
+				if image, err := store.Image(builder.FromImageID); err == nil && image.Created.After(until) {
+					continue
+				}
+			}
+			id := builder.ContainerID
+			if err := builder.Delete(); err != nil {
+				multiE = multierror.Append(multiE, errors.Wrapf(err, "error removing container %q", builder.Container))
+				continue
+			}
+			containersRemoved++
+			fmt.Printf("%s\n", id)
+		}
+	}
+
+	if pruneDanglingImages || pruneBuildCache {
+		systemContext, err := parse.SystemContextFromOptions(c)
+		if err != nil {
+			return err
+		}
+		runtime, err := libimage.RuntimeFromStore(store, &libimage.RuntimeOptions{SystemContext: systemContext})
+		if err != nil {
+			return err
+		}
+
+		var filterGroups [][]string
+		if pruneDanglingImages {
+			filterGroups = append(filterGroups, []string{"readonly=false", "dangling=true"})
+		}
+		if pruneBuildCache {
+			filterGroups = append(filterGroups, []string{"readonly=false", "intermediate=true"})
+		}
+		if iopts.all {
+			filterGroups = [][]string{{"readonly=false"}}
+		}
+
+		seen := make(map[string]bool)
+		for _, filters := range filterGroups {
+			options := &libimage.RemoveImagesOptions{
+				Filters:  filters,
+				Force:    iopts.force,
+				WithSize: true,
+			}
+			images, err := runtime.ListImages(context.Background(), nil, &libimage.ListImagesOptions{Filters: filters})
+			if err != nil {
+				return err
+			}
+			var names []string
+			for _, image := range images {
+				if seen[image.ID()] {
+					continue
+				}
+				if !until.IsZero() && image.Created().After(until) {
+					continue
+				}
+				names = append(names, image.ID())
+			}
+			if len(names) == 0 {
+				continue
+			}
+			reports, errs := runtime.RemoveImages(context.Background(), names, options)
+			for _, r := range reports {
+				seen[r.ID] = true
+				if r.Removed {
+					reclaimed += r.Size
+					fmt.Printf("%s\n", r.ID)
+				}
+			}
+			multiE = multierror.Append(multiE, errs...)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Total: containers removed: %d, space reclaimed: %s\n", containersRemoved, units.HumanSize(float64(reclaimed)))
+
+	return multiE.ErrorOrNil()
+}
+
+// parsePruneUntilFilter parses the "until=<duration>" prune filter into the
+// cutoff time before which containers and images are eligible for removal.
+// The returned time is the zero value if no "until" filter was given.
+func parsePruneUntilFilter(filters []string) (time.Time, error) {
+	for _, filter := range filters {
+		av := strings.SplitN(filter, "=", 2)
+		if len(av) != 2 || av[0] != "until" {
+			return time.Time{}, errors.Errorf("unrecognized prune filter %q: only \"until=<duration>\" is supported", filter)
+		}
+		duration, err := time.ParseDuration(av[1])
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "error parsing \"until\" filter value %q", av[1])
+		}
+		return time.Now().Add(-duration), nil
+	}
+	return time.Time{}, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/containers/buildah"
 	"github.com/containers/buildah/define"
 	buildahcli "github.com/containers/buildah/pkg/cli"
+	"github.com/containers/buildah/pkg/events"
 	"github.com/containers/buildah/pkg/parse"
 	"github.com/containers/buildah/util"
 	"github.com/containers/common/pkg/auth"
@@ -39,6 +40,8 @@ type pushOptions struct {
 	tlsVerify          bool
 	encryptionKeys     []string
 	encryptLayers      []int
+	verify             bool
+	signBySigstore     string
 }
 
 func init() {
@@ -66,6 +69,7 @@ func init() {
 		Example: `buildah push imageID docker://registry.example.com/repository:tag
   buildah push imageID docker-daemon:image:tagi
   buildah push imageID oci:/path/to/layout:image:tag`,
+		ValidArgsFunction: completeImages,
 	}
 	pushCommand.SetUsageTemplate(UsageTemplate())
 
@@ -216,6 +220,10 @@ func pushCmd(c *cobra.Command, args []string, iopts pushOptions) error {
 
 	logrus.Debugf("Successfully pushed %s with digest %s", transports.ImageName(dest), digest.String())
 
+	if err := events.Write(store, events.Event{Type: events.Push, Name: src, Args: []string{destSpec}}); err != nil {
+		logrus.Debugf("error recording push event: %v", err)
+	}
+
 	if iopts.digestfile != "" {
 		if err = ioutil.WriteFile(iopts.digestfile, []byte(digest.String()), 0644); err != nil {
 			return util.GetFailureCause(err, errors.Wrapf(err, "failed to write digest to file %q", iopts.digestfile))
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/containers/common/pkg/config"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// runOnConnection re-executes the current command line, minus the
+// --connection flag itself, as "buildah" on the named remote connection
+// (as configured in containers.conf's [engine.service_destinations]) by
+// shelling out to ssh, and then exits with the remote command's exit
+// status. It never returns on success.
+func runOnConnection(cmd *cobra.Command, name string) error {
+	conf, err := config.Default()
+	if err != nil {
+		return errors.Wrapf(err, "failed to get container config")
+	}
+	dest, ok := conf.Engine.ServiceDestinations[name]
+	if !ok {
+		return errors.Errorf("connection %q is not defined in containers.conf", name)
+	}
+	u, err := url.Parse(dest.URI)
+	if err != nil {
+		return errors.Wrapf(err, "error parsing URI for connection %q", name)
+	}
+	if u.Scheme != "ssh" {
+		return errors.Errorf("connection %q uses unsupported scheme %q (only ssh is supported)", name, u.Scheme)
+	}
+
+	sshBinary, err := exec.LookPath("ssh")
+	if err != nil {
+		return errors.Wrap(err, "remote connections require the ssh command to be installed")
+	}
+
+	sshArgs := []string{}
+	if dest.Identity != "" {
+		sshArgs = append(sshArgs, "-i", dest.Identity)
+	}
+	if port := u.Port(); port != "" {
+		sshArgs = append(sshArgs, "-p", port)
+	}
+	host := u.Hostname()
+	if u.User != nil {
+		host = u.User.Username() + "@" + host
+	}
+	sshArgs = append(sshArgs, host, "buildah")
+	sshArgs = append(sshArgs, remoteCommandArgs(os.Args[1:])...)
+
+	sshCmd := exec.Command(sshBinary, sshArgs...)
+	sshCmd.Stdin = os.Stdin
+	sshCmd.Stdout = os.Stdout
+	sshCmd.Stderr = os.Stderr
+	if err := sshCmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			os.Exit(exitErr.ExitCode())
+		}
+		return errors.Wrapf(err, "running buildah on connection %q", name)
+	}
+	os.Exit(0)
+	return nil
+}
+
+// remoteCommandArgs strips the --connection flag (and its value, in either
+// "--connection NAME" or "--connection=NAME" form) out of args, so that the
+// remainder can be passed through to buildah on the far end of the
+// connection unmodified.
+func remoteCommandArgs(args []string) []string {
+	out := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--connection":
+			i++ // also skip the value
+		case strings.HasPrefix(arg, "--connection="):
+			// value is embedded, nothing more to skip
+		default:
+			out = append(out, quoteIfNeeded(arg))
+		}
+	}
+	return out
+}
+
+// quoteIfNeeded single-quotes arg for inclusion in a remote POSIX shell
+// command line if it contains characters the shell would otherwise treat
+// specially.
+func quoteIfNeeded(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+	needsQuoting := false
+	for _, r := range arg {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("-_./:=@", r):
+		default:
+			needsQuoting = true
+		}
+	}
+	if !needsQuoting {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
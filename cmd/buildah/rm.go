@@ -5,8 +5,10 @@ import (
 	"os"
 
 	buildahcli "github.com/containers/buildah/pkg/cli"
+	"github.com/containers/buildah/pkg/events"
 	"github.com/containers/buildah/util"
 	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 )
 
@@ -30,6 +32,7 @@ func init() {
 		Example: `buildah rm containerID
   buildah rm containerID1 containerID2 containerID3
   buildah rm --all`,
+		ValidArgsFunction: completeContainers,
 	}
 	rmCommand.SetUsageTemplate(UsageTemplate())
 
@@ -70,6 +73,9 @@ func rmCmd(c *cobra.Command, args []string, iopts rmResults) error {
 				lastError = util.WriteError(os.Stderr, errors.Wrapf(err, "%s %q", delContainerErrStr, builder.Container), lastError)
 				continue
 			}
+			if err := events.Write(store, events.Event{Type: events.Remove, ID: id, Name: builder.Container}); err != nil {
+				logrus.Debugf("error recording rm event: %v", err)
+			}
 			fmt.Printf("%s\n", id)
 		}
 	} else {
@@ -84,6 +90,9 @@ func rmCmd(c *cobra.Command, args []string, iopts rmResults) error {
 				lastError = util.WriteError(os.Stderr, errors.Wrapf(err, "%s %q", delContainerErrStr, name), lastError)
 				continue
 			}
+			if err := events.Write(store, events.Event{Type: events.Remove, ID: id, Name: name}); err != nil {
+				logrus.Debugf("error recording rm event: %v", err)
+			}
 			fmt.Printf("%s\n", id)
 		}
 
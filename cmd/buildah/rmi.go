@@ -13,9 +13,10 @@ import (
 )
 
 type rmiOptions struct {
-	all   bool
-	prune bool
-	force bool
+	all    bool
+	prune  bool
+	force  bool
+	filter []string
 }
 
 func init() {
@@ -33,6 +34,7 @@ func init() {
 		Example: `buildah rmi imageID
   buildah rmi --all --force
   buildah rmi imageID1 imageID2 imageID3`,
+		ValidArgsFunction: completeImages,
 	}
 	rmiCommand.SetUsageTemplate(UsageTemplate())
 
@@ -42,12 +44,13 @@ func init() {
 	flags.BoolVarP(&opts.all, "all", "a", false, "remove all images")
 	flags.BoolVarP(&opts.prune, "prune", "p", false, "prune dangling images")
 	flags.BoolVarP(&opts.force, "force", "f", false, "force removal of the image and any containers using the image")
+	flags.StringArrayVar(&opts.filter, "filter", nil, "only remove images matching the given `filter`, e.g. dangling=true, until=<timestamp>, label=<key>[=<value>], reference=<pattern>")
 
 	rootCmd.AddCommand(rmiCommand)
 }
 
 func rmiCmd(c *cobra.Command, args []string, iopts rmiOptions) error {
-	if len(args) == 0 && !iopts.all && !iopts.prune {
+	if len(args) == 0 && !iopts.all && !iopts.prune && len(iopts.filter) == 0 {
 		return errors.Errorf("image name or ID must be specified")
 	}
 	if len(args) > 0 && iopts.all {
@@ -86,6 +89,7 @@ func rmiCmd(c *cobra.Command, args []string, iopts rmiOptions) error {
 	} else if !iopts.all {
 		options.Filters = append(options.Filters, "intermediate=false")
 	}
+	options.Filters = append(options.Filters, iopts.filter...)
 	options.Force = iopts.force
 
 	rmiReports, rmiErrors := runtime.RemoveImages(context.Background(), args, options)
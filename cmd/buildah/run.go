@@ -1,11 +1,14 @@
 package main
 
 import (
+	"fmt"
 	"os"
 	"strings"
 
 	"github.com/containers/buildah"
 	buildahcli "github.com/containers/buildah/pkg/cli"
+	"github.com/containers/buildah/pkg/completion"
+	"github.com/containers/buildah/pkg/events"
 	"github.com/containers/buildah/pkg/parse"
 	"github.com/containers/buildah/util"
 	"github.com/pkg/errors"
@@ -14,19 +17,23 @@ import (
 )
 
 type runInputOptions struct {
-	addHistory  bool
-	capAdd      []string
-	capDrop     []string
-	env         []string
-	hostname    string
-	isolation   string
-	mounts      []string
-	runtime     string
-	runtimeFlag []string
-	noPivot     bool
-	terminal    bool
-	volumes     []string
-	workingDir  string
+	addHistory    bool
+	capAdd        []string
+	capDrop       []string
+	env           []string
+	groupAdd      []string
+	hostname      string
+	isolation     string
+	mounts        []string
+	runtime       string
+	runtimeFlag   []string
+	noPivot       bool
+	readOnly      bool
+	readOnlyTmpfs bool
+	securityOpt   []string
+	terminal      bool
+	volumes       []string
+	workingDir    string
 	*buildahcli.NameSpaceResults
 }
 
@@ -50,6 +57,7 @@ func init() {
 		Example: `buildah run containerID -- ps -auxw
   buildah run --terminal containerID /bin/bash
   buildah run --volume /path/on/host:/path/in/container:ro,z containerID /bin/sh`,
+		ValidArgsFunction: completeContainers,
 	}
 	runCommand.SetUsageTemplate(UsageTemplate())
 
@@ -59,12 +67,16 @@ func init() {
 	flags.StringSliceVar(&opts.capAdd, "cap-add", []string{}, "add the specified capability (default [])")
 	flags.StringSliceVar(&opts.capDrop, "cap-drop", []string{}, "drop the specified capability (default [])")
 	flags.StringArrayVarP(&opts.env, "env", "e", []string{}, "add environment variable to be set temporarily when running command (default [])")
+	flags.StringArrayVar(&opts.groupAdd, "group-add", []string{}, "add additional groups, or 'keep-groups' to keep the current supplemental group list, to the primary process (default [])")
 	flags.StringVar(&opts.hostname, "hostname", "", "set the hostname inside of the container")
 	flags.StringVar(&opts.isolation, "isolation", "", "`type` of process isolation to use. Use BUILDAH_ISOLATION environment variable to override.")
 	// Do not set a default runtime here, we'll do that later in the processing.
 	flags.StringVar(&opts.runtime, "runtime", util.Runtime(), "`path` to an alternate OCI runtime")
 	flags.StringSliceVar(&opts.runtimeFlag, "runtime-flag", []string{}, "add global flags for the container runtime")
 	flags.BoolVar(&opts.noPivot, "no-pivot", false, "do not use pivot root to jail process inside rootfs")
+	flags.BoolVar(&opts.readOnly, "read-only", false, "mount the container's root filesystem read-only for this run")
+	flags.BoolVar(&opts.readOnlyTmpfs, "read-only-tmpfs", true, "when --read-only is set, mount tmpfs over /tmp, /run, and /var/tmp")
+	flags.StringArrayVar(&opts.securityOpt, "security-opt", []string{}, "security options for this run, overriding those set for the container (default [])")
 	flags.BoolVarP(&opts.terminal, "terminal", "t", false, "allocate a pseudo-TTY in the container")
 	flags.StringArrayVarP(&opts.volumes, "volume", "v", []string{}, "bind mount a host location into the container while running the command")
 	flags.StringArrayVar(&opts.mounts, "mount", []string{}, "Attach a filesystem mount to the container (default [])")
@@ -77,6 +89,10 @@ func init() {
 	flags.AddFlagSet(&namespaceFlags)
 	flags.SetNormalizeFunc(buildahcli.AliasFlags)
 
+	if err := runCommand.RegisterFlagCompletionFunc("isolation", completion.AutocompleteIsolation); err != nil {
+		panic(fmt.Sprintf("error registering completion function for --isolation flag: %v", err))
+	}
+
 	rootCmd.AddCommand(runCommand)
 }
 
@@ -121,21 +137,32 @@ func runCmd(c *cobra.Command, args []string, iopts runInputOptions) error {
 		return err
 	}
 
+	apparmorProfile, seccompProfilePath, err := parse.SecurityOptions(c)
+	if err != nil {
+		return err
+	}
+
 	options := buildah.RunOptions{
-		Hostname:         iopts.hostname,
-		Runtime:          iopts.runtime,
-		Args:             runtimeFlags,
-		NoPivot:          noPivot,
-		User:             c.Flag("user").Value.String(),
-		Isolation:        isolation,
-		NamespaceOptions: namespaceOptions,
-		ConfigureNetwork: networkPolicy,
-		CNIPluginPath:    iopts.CNIPlugInPath,
-		CNIConfigDir:     iopts.CNIConfigDir,
-		AddCapabilities:  iopts.capAdd,
-		DropCapabilities: iopts.capDrop,
-		Env:              iopts.env,
-		WorkingDir:       iopts.workingDir,
+		Context:            getContext(),
+		Hostname:           iopts.hostname,
+		Runtime:            iopts.runtime,
+		Args:               runtimeFlags,
+		NoPivot:            noPivot,
+		User:               c.Flag("user").Value.String(),
+		Isolation:          isolation,
+		NamespaceOptions:   namespaceOptions,
+		ConfigureNetwork:   networkPolicy,
+		CNIPluginPath:      iopts.CNIPlugInPath,
+		CNIConfigDir:       iopts.CNIConfigDir,
+		AddCapabilities:    iopts.capAdd,
+		DropCapabilities:   iopts.capDrop,
+		Env:                iopts.env,
+		GroupAdd:           iopts.groupAdd,
+		ReadOnly:           iopts.readOnly,
+		ReadOnlyTmpfs:      iopts.readOnlyTmpfs,
+		WorkingDir:         iopts.workingDir,
+		ApparmorProfile:    apparmorProfile,
+		SeccompProfilePath: seccompProfilePath,
 	}
 
 	if c.Flag("terminal").Changed {
@@ -152,11 +179,20 @@ func runCmd(c *cobra.Command, args []string, iopts runInputOptions) error {
 	}
 	options.Mounts = mounts
 
+	if options.Terminal == buildah.WithTerminal {
+		resize, stopResizing := runResizeChannel()
+		defer stopResizing()
+		options.Resize = resize
+	}
+
 	runerr := builder.Run(args, options)
 	if runerr != nil {
 		logrus.Debugf("error running %v in container %q: %v", args, builder.Container, runerr)
 	}
 	if runerr == nil {
+		if err := events.Write(store, events.Event{Type: events.Run, ID: builder.ContainerID, Name: builder.Container, Args: args}); err != nil {
+			logrus.Debugf("error recording run event: %v", err)
+		}
 		shell := "/bin/sh -c"
 		if len(builder.Shell()) > 0 {
 			shell = strings.Join(builder.Shell(), " ")
@@ -0,0 +1,42 @@
+//go:build linux
+// +build linux
+
+package main
+
+import (
+	"os"
+	"os/signal"
+
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"golang.org/x/sys/unix"
+)
+
+// runResizeChannel watches for SIGWINCH and translates it into a stream of
+// terminal sizes read from stdin, for use as a buildah.RunOptions.Resize
+// channel.  The returned function should be called once the command being
+// run has finished, to stop watching for the signal.
+func runResizeChannel() (<-chan specs.Box, func()) {
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, unix.SIGWINCH)
+	resize := make(chan specs.Box)
+	stop := make(chan struct{})
+	go func() {
+		defer close(resize)
+		for {
+			select {
+			case <-winch:
+				winsize, err := unix.IoctlGetWinsize(unix.Stdin, unix.TIOCGWINSZ)
+				if err != nil {
+					continue
+				}
+				resize <- specs.Box{Height: uint(winsize.Row), Width: uint(winsize.Col)}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return resize, func() {
+		signal.Stop(winch)
+		close(stop)
+	}
+}
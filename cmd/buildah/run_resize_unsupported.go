@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package main
+
+import (
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// runResizeChannel is a no-op on platforms where buildah run isn't
+// supported in the first place.
+func runResizeChannel() (<-chan specs.Box, func()) {
+	return nil, func() {}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/containers/buildah"
+	"github.com/containers/buildah/define"
+	"github.com/containers/buildah/pkg/parse"
+	"github.com/containers/buildah/pkg/scan"
+	storageTransport "github.com/containers/image/v5/storage"
+	"github.com/containers/image/v5/types"
+	"github.com/containers/storage"
+	"github.com/containers/storage/pkg/stringid"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// scanOfLabel records, on an artifact image produced by a scan, the name of
+// the image that was scanned to produce it.
+const scanOfLabel = "io.buildah.scan-of"
+
+type scanOptions struct {
+	scanner    string
+	scannerArg []string
+	failOn     string
+	format     string
+}
+
+func init() {
+	var (
+		opts            scanOptions
+		scanDescription = `  Runs a configurable external vulnerability scanner against IMAGE and
+  records the results as an artifact in local storage.  The scanner is
+  invoked as "SCANNER [--scanner-arg]... IMAGE" and is expected to print a
+  JSON report to its standard output; see the buildah-scan(1) man page for
+  the exact contract.`
+	)
+	scanCommand := &cobra.Command{
+		Use:   "scan IMAGE",
+		Short: "Scan an image for known vulnerabilities",
+		Long:  scanDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return scanCmd(cmd, args, opts)
+		},
+		Example:           `buildah scan --scanner grype-json-shim registry.example.com/repository/image:tag`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeImages,
+	}
+	scanCommand.SetUsageTemplate(UsageTemplate())
+	flags := scanCommand.Flags()
+	flags.StringVar(&opts.scanner, "scanner", "", "`command` to invoke to scan the image (for example, a wrapper around grype or trivy)")
+	flags.StringArrayVar(&opts.scannerArg, "scanner-arg", nil, "additional `argument` to pass to the scanner command before the image reference (may be used more than once)")
+	flags.StringVar(&opts.failOn, "fail-on", "", "exit with an error if a vulnerability at or above this `severity` is found")
+	flags.StringVar(&opts.format, "format", "", "alternate output `format`, one of \"json\"")
+
+	rootCmd.AddCommand(scanCommand)
+}
+
+func scanCmd(c *cobra.Command, args []string, iopts scanOptions) error {
+	image := args[0]
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+
+	result, err := runScanner(getContext(), image, iopts.scanner, iopts.scannerArg)
+	if err != nil {
+		return err
+	}
+
+	if id, err := recordScanArtifact(getContext(), store, systemContext, image, result); err != nil {
+		logrus.Errorf("error recording scan results for %q as an artifact: %v", image, err)
+	} else {
+		logrus.Debugf("recorded scan results for %q as artifact %s", image, id)
+	}
+
+	if err := printScanResult(result, iopts.format); err != nil {
+		return err
+	}
+
+	if result.ExceedsSeverity(iopts.failOn) {
+		return errors.Errorf("image %q has a vulnerability at or above severity %q", image, iopts.failOn)
+	}
+	return nil
+}
+
+// runScanner invokes the configured scanner command against imageRef.
+func runScanner(ctx context.Context, imageRef, scanner string, scannerArgs []string) (*scan.Result, error) {
+	if scanner == "" {
+		return nil, errors.New("--scanner is required: specify the scanner command to invoke")
+	}
+	result, err := scan.Run(ctx, imageRef, scan.Options{Command: scanner, Args: scannerArgs})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error scanning %q", imageRef)
+	}
+	return result, nil
+}
+
+func printScanResult(result *scan.Result, format string) error {
+	if format == "json" {
+		data, err := json.MarshalIndent(result, "", "    ")
+		if err != nil {
+			return errors.Wrapf(err, "error formatting scan results")
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(result.Vulnerabilities) == 0 {
+		fmt.Println("No vulnerabilities reported")
+		return nil
+	}
+	for _, v := range result.Vulnerabilities {
+		fmt.Printf("%s\t%s\t%s\t%s\n", v.Severity, v.ID, v.Package, v.Version)
+	}
+	return nil
+}
+
+// recordScanArtifact packages a scan's JSON output as a "buildah artifact"
+// image labeled with the name of the image it was scanned from, so that
+// "buildah artifact ls" and "buildah artifact rm" can find and manage
+// scan reports the same way they manage any other artifact.
+func recordScanArtifact(ctx context.Context, store storage.Store, systemContext *types.SystemContext, scannedImage string, result *scan.Result) (string, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return "", errors.Wrapf(err, "error marshaling scan results")
+	}
+
+	reportFile, err := ioutil.TempFile("", "buildah-scan-*.json")
+	if err != nil {
+		return "", errors.Wrapf(err, "error creating temporary scan report file")
+	}
+	defer os.Remove(reportFile.Name())
+	if _, err := reportFile.Write(data); err != nil {
+		reportFile.Close()
+		return "", errors.Wrapf(err, "error writing temporary scan report file")
+	}
+	if err := reportFile.Close(); err != nil {
+		return "", errors.Wrapf(err, "error writing temporary scan report file")
+	}
+
+	options := buildah.BuilderOptions{
+		FromImage:       "scratch",
+		SystemContext:   systemContext,
+		CommonBuildOpts: &define.CommonBuildOptions{},
+	}
+	builder, err := buildah.NewBuilder(ctx, store, options)
+	if err != nil {
+		return "", errors.Wrapf(err, "error creating scan artifact container")
+	}
+	defer func() {
+		if err := builder.Delete(); err != nil {
+			logrus.Debugf("error cleaning up scan artifact container: %v", err)
+		}
+	}()
+
+	if err := builder.Add("scan.json", false, buildah.AddAndCopyOptions{}, reportFile.Name()); err != nil {
+		return "", errors.Wrapf(err, "error adding scan report to artifact")
+	}
+	builder.SetLabel(artifactLabel, "true")
+	builder.SetLabel(scanOfLabel, scannedImage)
+
+	name := "localhost/" + stringid.GenerateRandomID()[:12] + "-scan:latest"
+	dest, err := storageTransport.Transport.ParseStoreReference(store, name)
+	if err != nil {
+		return "", errors.Wrapf(err, "error parsing artifact name %q", name)
+	}
+
+	id, _, _, err := builder.Commit(ctx, dest, buildah.CommitOptions{SystemContext: systemContext})
+	if err != nil {
+		return "", errors.Wrapf(err, "error committing scan artifact %q", name)
+	}
+	return id, nil
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/containers/buildah/define"
+	"github.com/containers/buildah/imagebuildah"
+	"github.com/containers/buildah/pkg/buildkitgateway"
+	"github.com/containers/buildah/pkg/parse"
+	"github.com/containers/storage"
+	"github.com/containers/storage/pkg/archive"
+	"github.com/containers/storage/pkg/unshare"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type serveOptions struct {
+	address         string
+	buildkitGateway bool
+}
+
+func init() {
+	var (
+		opts             serveOptions
+		serveDescription = "\n  Runs a REST API over a Unix socket, so that orchestration systems can\n  submit build contexts, stream build logs, and check on or cancel builds\n  without exec'ing the buildah CLI directly."
+	)
+	serveCommand := &cobra.Command{
+		Use:   "serve",
+		Short: "Run a REST API for driving builds remotely",
+		Long:  serveDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serveCmd(cmd, args, opts)
+		},
+		Example: `buildah serve
+  buildah serve --address /run/buildah/buildah.sock`,
+		Args: cobra.NoArgs,
+	}
+	serveCommand.SetUsageTemplate(UsageTemplate())
+
+	flags := serveCommand.Flags()
+	flags.StringVar(&opts.address, "address", "", "path of the Unix socket to listen on (default is $XDG_RUNTIME_DIR/buildah.sock, or /run/buildah/buildah.sock)")
+	flags.BoolVar(&opts.buildkitGateway, "buildkit-gateway", false, "speak the BuildKit gateway/control gRPC protocol on the socket instead of the buildah REST API (not supported in this build)")
+
+	rootCmd.AddCommand(serveCommand)
+}
+
+// buildJob tracks the state of one "buildah serve" build, from submission
+// through completion, so that /v1/builds/{id} and /v1/builds/{id}/logs can
+// report on it after the request that started it has returned.
+type buildJob struct {
+	id string
+
+	mu       sync.Mutex
+	log      strings.Builder
+	done     bool
+	err      error
+	imageID  string
+	imageRef string
+
+	cancel context.CancelFunc
+}
+
+func (j *buildJob) Write(p []byte) (int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.log.Write(p)
+}
+
+func (j *buildJob) finish(imageID, imageRef string, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.done = true
+	j.imageID = imageID
+	j.imageRef = imageRef
+	j.err = err
+}
+
+func (j *buildJob) snapshot() (log string, done bool, imageID, imageRef string, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.log.String(), j.done, j.imageID, j.imageRef, j.err
+}
+
+// buildServer holds the job registry backing the "buildah serve" API.
+type buildServer struct {
+	store  storage.Store
+	cmd    *cobra.Command
+	mu     sync.Mutex
+	jobs   map[string]*buildJob
+	nextID int64
+}
+
+func newBuildServer(store storage.Store, cmd *cobra.Command) *buildServer {
+	return &buildServer{store: store, cmd: cmd, jobs: make(map[string]*buildJob)}
+}
+
+func (s *buildServer) newJob() *buildJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	job := &buildJob{id: strconv.FormatInt(s.nextID, 10)}
+	s.jobs[job.id] = job
+	return job
+}
+
+func (s *buildServer) getJob(id string) *buildJob {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jobs[id]
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{Error: err.Error()})
+}
+
+// handleSubmit accepts a POST of a tar-encoded build context (optionally
+// with a "dockerfile" query parameter naming the Containerfile within it,
+// and a "tag" query parameter naming the image to produce), starts a build
+// running in the background, and returns the ID it can be tracked by.
+func (s *buildServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSONError(w, http.StatusMethodNotAllowed, errors.New("only POST is supported"))
+		return
+	}
+
+	dockerfile := r.URL.Query().Get("dockerfile")
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	tag := r.URL.Query().Get("tag")
+
+	contextDir, err := ioutil.TempDir("", "buildah-serve")
+	if err != nil {
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err := archive.Untar(r.Body, contextDir, nil); err != nil {
+		os.RemoveAll(contextDir)
+		writeJSONError(w, http.StatusBadRequest, errors.Wrapf(err, "error extracting build context"))
+		return
+	}
+
+	systemContext, err := parse.SystemContextFromOptions(s.cmd)
+	if err != nil {
+		os.RemoveAll(contextDir)
+		writeJSONError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	job := s.newJob()
+	ctx, cancel := context.WithCancel(context.Background())
+	job.cancel = cancel
+
+	go func() {
+		defer os.RemoveAll(contextDir)
+		options := define.BuildOptions{
+			ContextDirectory: contextDir,
+			Output:           tag,
+			Out:              job,
+			Err:              job,
+			ReportWriter:     job,
+			SystemContext:    systemContext,
+			CommonBuildOpts:  &define.CommonBuildOptions{},
+		}
+		imageID, ref, err := imagebuildah.BuildDockerfiles(ctx, s.store, options, filepath.Join(contextDir, dockerfile))
+		imageRef := ""
+		if ref != nil {
+			imageRef = ref.String()
+		}
+		job.finish(imageID, imageRef, err)
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/v1/builds/"+job.id)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(struct {
+		ID string `json:"id"`
+	}{ID: job.id})
+}
+
+// handleBuild serves GET (status) and DELETE (cancel) for a single build,
+// and GET .../logs to fetch its accumulated build log.
+func (s *buildServer) handleBuild(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/v1/builds/")
+	wantLogs := false
+	if strings.HasSuffix(rest, "/logs") {
+		wantLogs = true
+		rest = strings.TrimSuffix(rest, "/logs")
+	}
+	job := s.getJob(rest)
+	if job == nil {
+		writeJSONError(w, http.StatusNotFound, errors.Errorf("no such build %q", rest))
+		return
+	}
+
+	log, done, imageID, imageRef, err := job.snapshot()
+
+	if wantLogs {
+		if r.Method != http.MethodGet {
+			writeJSONError(w, http.StatusMethodNotAllowed, errors.New("only GET is supported"))
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, log)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		status := struct {
+			ID       string `json:"id"`
+			Done     bool   `json:"done"`
+			ImageID  string `json:"imageId,omitempty"`
+			ImageRef string `json:"imageRef,omitempty"`
+			Error    string `json:"error,omitempty"`
+		}{ID: job.id, Done: done, ImageID: imageID, ImageRef: imageRef}
+		if err != nil {
+			status.Error = err.Error()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(status)
+	case http.MethodDelete:
+		job.cancel()
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		writeJSONError(w, http.StatusMethodNotAllowed, errors.New("only GET and DELETE are supported"))
+	}
+}
+
+func defaultServeAddress() string {
+	if unshare.IsRootless() {
+		if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+			return filepath.Join(runtimeDir, "buildah.sock")
+		}
+	}
+	return "/run/buildah/buildah.sock"
+}
+
+func serveCmd(c *cobra.Command, args []string, iopts serveOptions) error {
+	if err := setXDGRuntimeDir(); err != nil {
+		return err
+	}
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+
+	address := iopts.address
+	if address == "" {
+		address = defaultServeAddress()
+	}
+	if err := os.MkdirAll(filepath.Dir(address), 0700); err != nil {
+		return errors.Wrapf(err, "error creating %q", filepath.Dir(address))
+	}
+	if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error removing stale socket %q", address)
+	}
+
+	listener, err := net.Listen("unix", address)
+	if err != nil {
+		return errors.Wrapf(err, "error listening on %q", address)
+	}
+	defer listener.Close()
+
+	if iopts.buildkitGateway {
+		return buildkitgateway.Serve(getContext(), listener)
+	}
+
+	server := newBuildServer(store, c)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/builds", server.handleSubmit)
+	mux.HandleFunc("/v1/builds/", server.handleBuild)
+
+	logrus.Infof("buildah serve listening on %s", address)
+	return http.Serve(listener, mux)
+}
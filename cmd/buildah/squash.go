@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/containers/buildah"
+	"github.com/containers/buildah/define"
+	buildahcli "github.com/containers/buildah/pkg/cli"
+	"github.com/containers/buildah/pkg/parse"
+	"github.com/containers/image/v5/pkg/shortnames"
+	storageTransport "github.com/containers/image/v5/storage"
+	"github.com/containers/image/v5/transports/alltransports"
+	"github.com/containers/image/v5/types"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+type squashOptions struct {
+	iidfile string
+}
+
+func init() {
+	var (
+		opts              squashOptions
+		squashDescription = "\n  Produces a new image with an existing local image's layers squashed into a\n  single layer, preserving its configuration and history metadata, so images\n  built elsewhere can be flattened without rebuilding them."
+	)
+	squashCommand := &cobra.Command{
+		Use:   "squash IMAGE [NEW-IMAGE]",
+		Short: "Squash the layers of an existing image",
+		Long:  squashDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return squashCmd(cmd, args, opts)
+		},
+		Example: `buildah squash imageID
+  buildah squash imageID newImageName`,
+		Args: cobra.RangeArgs(1, 2),
+	}
+	squashCommand.SetUsageTemplate(UsageTemplate())
+
+	flags := squashCommand.Flags()
+	flags.StringVar(&opts.iidfile, "iidfile", "", "write the image ID to the file")
+
+	rootCmd.AddCommand(squashCommand)
+}
+
+func squashCmd(c *cobra.Command, args []string, iopts squashOptions) error {
+	if err := buildahcli.VerifyFlagsArgsOrder(args); err != nil {
+		return err
+	}
+	image := args[0]
+	newImage := ""
+	if len(args) > 1 {
+		newImage = args[1]
+	}
+
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+	ctx := getContext()
+
+	builder, err := buildah.NewBuilder(ctx, store, buildah.BuilderOptions{
+		FromImage:     image,
+		PullPolicy:    define.PullNever,
+		SystemContext: systemContext,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error reading image %q", image)
+	}
+	defer func() {
+		if err := builder.Delete(); err != nil {
+			fmt.Fprintf(os.Stderr, "error removing temporary working container: %v\n", err)
+		}
+	}()
+
+	var dest types.ImageReference
+	if newImage != "" {
+		if dest, err = alltransports.ParseImageName(newImage); err != nil {
+			candidates, err := shortnames.ResolveLocally(systemContext, newImage)
+			if err != nil {
+				return err
+			}
+			if len(candidates) == 0 {
+				return errors.Errorf("error parsing target image name %q", newImage)
+			}
+			dest2, err2 := storageTransport.Transport.ParseStoreReference(store, candidates[0].String())
+			if err2 != nil {
+				return errors.Wrapf(err, "error parsing target image name %q", newImage)
+			}
+			dest = dest2
+		}
+	}
+
+	builder.SetLabel(buildah.BuilderIdentityAnnotation, define.Version)
+	id, _, _, err := builder.Commit(ctx, dest, buildah.CommitOptions{
+		SystemContext: systemContext,
+		Squash:        true,
+		IIDFile:       iopts.iidfile,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error squashing image %q", image)
+	}
+	if iopts.iidfile == "" && id != "" {
+		fmt.Printf("%s\n", id)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/containers/storage"
+	units "github.com/docker/go-units"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type statsOptions struct {
+	noStream bool
+	format   string
+}
+
+// containerStats is one container's resource-usage snapshot, as reported
+// by "buildah stats".
+type containerStats struct {
+	ContainerID   string  `json:"containerID"`
+	ContainerName string  `json:"containerName"`
+	CPUSeconds    float64 `json:"cpuSeconds"`
+	MemoryUsage   uint64  `json:"memoryUsageBytes"`
+	MemoryLimit   uint64  `json:"memoryLimitBytes,omitempty"`
+	BlockRead     uint64  `json:"blockReadBytes"`
+	BlockWrite    uint64  `json:"blockWriteBytes"`
+}
+
+// runStateInfo mirrors the JSON that the root package writes, for a
+// container, to record that a "buildah run" step is currently executing
+// for it. See (*Builder).recordRunState in run_linux.go.
+type runStateInfo struct {
+	ContainerName string   `json:"containerName"`
+	BundlePath    string   `json:"bundlePath"`
+	Runtime       string   `json:"runtime"`
+	RuntimeArgs   []string `json:"runtimeArgs"`
+}
+
+func init() {
+	var (
+		opts             statsOptions
+		statsDescription = `  Reports live CPU, memory, and block I/O usage for containers which
+  currently have a "buildah run" step executing, by asking the OCI runtime
+  for the running container's state and reading its cgroup.`
+	)
+	statsCommand := &cobra.Command{
+		Use:   "stats",
+		Short: "Display resource usage statistics for running \"buildah run\" containers",
+		Long:  statsDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return statsCmd(cmd, args, opts)
+		},
+		Args: cobra.NoArgs,
+	}
+	statsCommand.SetUsageTemplate(UsageTemplate())
+	flags := statsCommand.Flags()
+	flags.BoolVar(&opts.noStream, "no-stream", false, "print one snapshot and exit, instead of refreshing continuously")
+	flags.StringVar(&opts.format, "format", "", "alternate output `format`, one of \"json\"")
+
+	rootCmd.AddCommand(statsCommand)
+}
+
+func statsCmd(c *cobra.Command, args []string, iopts statsOptions) error {
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+
+	for {
+		stats, err := collectContainerStats(store)
+		if err != nil {
+			return err
+		}
+		if err := printContainerStats(stats, iopts.format); err != nil {
+			return err
+		}
+		if iopts.noStream {
+			return nil
+		}
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// collectContainerStats looks at every container in local storage, and for
+// each one that's currently running a "buildah run" step, reads its live
+// resource usage.
+func collectContainerStats(store storage.Store) ([]containerStats, error) {
+	containers, err := store.Containers()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading list of containers")
+	}
+	var results []containerStats
+	for _, c := range containers {
+		cdir, err := store.ContainerDirectory(c.ID)
+		if err != nil {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(cdir, "run-state.json"))
+		if err != nil {
+			// No "buildah run" is currently executing for this container.
+			continue
+		}
+		var info runStateInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			logrus.Debugf("error parsing run state for container %q: %v", c.ID, err)
+			continue
+		}
+		pid, err := runningContainerPID(info)
+		if err != nil {
+			logrus.Debugf("error reading runtime state for container %q: %v", c.ID, err)
+			continue
+		}
+		stat, err := statsForPID(pid)
+		if err != nil {
+			logrus.Debugf("error reading resource usage for container %q: %v", c.ID, err)
+			continue
+		}
+		stat.ContainerID = c.ID
+		stat.ContainerName = c.ID
+		if len(c.Names) > 0 {
+			stat.ContainerName = c.Names[0]
+		}
+		results = append(results, *stat)
+	}
+	return results, nil
+}
+
+// runningContainerPID asks the OCI runtime that's managing a "buildah run"
+// step for its current state, the same way the run step itself polls for
+// completion, and returns the PID of the running container process.
+func runningContainerPID(info runStateInfo) (int, error) {
+	args := append(append([]string{}, info.RuntimeArgs...), "state", info.ContainerName)
+	cmd := exec.Command(info.Runtime, args...)
+	cmd.Dir = info.BundlePath
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, errors.Wrapf(err, "error querying %s for the state of %q", info.Runtime, info.ContainerName)
+	}
+	var state specs.State
+	if err := json.Unmarshal(output, &state); err != nil {
+		return 0, errors.Wrapf(err, "error parsing state of %q", info.ContainerName)
+	}
+	if state.Status != "running" {
+		return 0, errors.Errorf("%q is not running (status %q)", info.ContainerName, state.Status)
+	}
+	return state.Pid, nil
+}
+
+func printContainerStats(stats []containerStats, format string) error {
+	if format == "json" {
+		data, err := json.MarshalIndent(stats, "", "    ")
+		if err != nil {
+			return errors.Wrapf(err, "error formatting stats")
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	if len(stats) == 0 {
+		fmt.Println("No containers are currently running \"buildah run\" steps")
+		return nil
+	}
+	fmt.Printf("%-16s%-24s%-12s%-14s%-12s%-12s\n", "CONTAINER", "NAME", "CPU (s)", "MEM USAGE", "BLOCK IN", "BLOCK OUT")
+	for _, s := range stats {
+		fmt.Printf("%-16s%-24s%-12.2f%-14s%-12s%-12s\n",
+			truncateID(s.ContainerID, true),
+			s.ContainerName,
+			s.CPUSeconds,
+			units.HumanSize(float64(s.MemoryUsage)),
+			units.HumanSize(float64(s.BlockRead)),
+			units.HumanSize(float64(s.BlockWrite)))
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/containers/buildah/util"
+	"github.com/pkg/errors"
+)
+
+// statsForPID reads live CPU, memory, and block I/O usage for the cgroup
+// that the given process belongs to, from the same /proc and /sys/fs/cgroup
+// files that "buildah run"'s container is accounted against.
+func statsForPID(pid int) (*containerStats, error) {
+	cgroupPaths, err := processCgroupPaths(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	unified, err := util.IsCgroup2UnifiedMode()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error determining cgroup version")
+	}
+	if unified {
+		return statsFromCgroupV2(cgroupPaths["unified"])
+	}
+	return statsFromCgroupV1(cgroupPaths)
+}
+
+// processCgroupPaths parses /proc/<pid>/cgroup into a map of controller
+// name to the process's cgroup path for that controller.  Under the
+// unified (v2) hierarchy, the single entry uses the key "unified".
+func processCgroupPaths(pid int) (map[string]string, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading cgroup membership for pid %d", pid)
+	}
+	defer f.Close()
+
+	paths := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		controllers, path := fields[1], fields[2]
+		if controllers == "" {
+			paths["unified"] = path
+			continue
+		}
+		for _, controller := range strings.Split(controllers, ",") {
+			paths[controller] = path
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "error parsing cgroup membership for pid %d", pid)
+	}
+	return paths, nil
+}
+
+func statsFromCgroupV2(path string) (*containerStats, error) {
+	if path == "" {
+		return nil, errors.New("no unified cgroup path found")
+	}
+	dir := filepath.Join("/sys/fs/cgroup", path)
+	stats := &containerStats{}
+
+	if usec, err := readKeyedUint64(filepath.Join(dir, "cpu.stat"), "usage_usec"); err == nil {
+		stats.CPUSeconds = float64(usec) / 1000000
+	}
+	if usage, err := readUint64File(filepath.Join(dir, "memory.current")); err == nil {
+		stats.MemoryUsage = usage
+	}
+	if limit, err := readTextFile(filepath.Join(dir, "memory.max")); err == nil && limit != "max" {
+		if v, err := strconv.ParseUint(limit, 10, 64); err == nil {
+			stats.MemoryLimit = v
+		}
+	}
+	if read, write, err := readIOStatV2(filepath.Join(dir, "io.stat")); err == nil {
+		stats.BlockRead = read
+		stats.BlockWrite = write
+	}
+	return stats, nil
+}
+
+func statsFromCgroupV1(cgroupPaths map[string]string) (*containerStats, error) {
+	stats := &containerStats{}
+
+	if path, ok := cgroupPaths["cpuacct"]; ok {
+		if usage, err := readUint64File(filepath.Join("/sys/fs/cgroup/cpuacct", path, "cpuacct.usage")); err == nil {
+			stats.CPUSeconds = float64(usage) / 1000000000
+		}
+	}
+	if path, ok := cgroupPaths["memory"]; ok {
+		dir := filepath.Join("/sys/fs/cgroup/memory", path)
+		if usage, err := readUint64File(filepath.Join(dir, "memory.usage_in_bytes")); err == nil {
+			stats.MemoryUsage = usage
+		}
+		if limit, err := readUint64File(filepath.Join(dir, "memory.limit_in_bytes")); err == nil && limit < 1<<62 {
+			stats.MemoryLimit = limit
+		}
+	}
+	if path, ok := cgroupPaths["blkio"]; ok {
+		dir := filepath.Join("/sys/fs/cgroup/blkio", path)
+		if read, write, err := readBlkioServiceBytes(filepath.Join(dir, "blkio.throttle.io_service_bytes")); err == nil {
+			stats.BlockRead = read
+			stats.BlockWrite = write
+		}
+	}
+	return stats, nil
+}
+
+func readTextFile(path string) (string, error) {
+	data, err := readFileBytes(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func readUint64File(path string) (uint64, error) {
+	text, err := readTextFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(text, 10, 64)
+}
+
+func readKeyedUint64(path, key string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == key {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	return 0, errors.Errorf("key %q not found in %s", key, path)
+}
+
+func readIOStatV2(path string) (read uint64, write uint64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		for _, field := range strings.Fields(scanner.Text()) {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch kv[0] {
+			case "rbytes":
+				if v, err := strconv.ParseUint(kv[1], 10, 64); err == nil {
+					read += v
+				}
+			case "wbytes":
+				if v, err := strconv.ParseUint(kv[1], 10, 64); err == nil {
+					write += v
+				}
+			}
+		}
+	}
+	return read, write, scanner.Err()
+}
+
+func readBlkioServiceBytes(path string) (read uint64, write uint64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[1] {
+		case "Read":
+			read += value
+		case "Write":
+			write += value
+		}
+	}
+	return read, write, scanner.Err()
+}
+
+func readFileBytes(path string) ([]byte, error) {
+	return ioutil.ReadFile(path)
+}
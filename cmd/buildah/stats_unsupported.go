@@ -0,0 +1,12 @@
+//go:build !linux
+// +build !linux
+
+package main
+
+import "github.com/pkg/errors"
+
+// statsForPID is not implemented on this platform: reading cgroup-based
+// resource usage requires Linux.
+func statsForPID(pid int) (*containerStats, error) {
+	return nil, errors.New("reading container resource usage is only supported on Linux")
+}
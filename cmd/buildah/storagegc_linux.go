@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"syscall"
+
+	"github.com/containers/common/libimage"
+	"github.com/containers/image/v5/types"
+	"github.com/containers/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// storageUsedPercent returns the fraction, from 0 to 100, of the filesystem
+// backing root that's currently in use.
+func storageUsedPercent(root string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(root, &stat); err != nil {
+		return 0, err
+	}
+	if stat.Blocks == 0 {
+		return 0, nil
+	}
+	used := stat.Blocks - stat.Bfree
+	return used * 100 / stat.Blocks, nil
+}
+
+// maybePruneForStorageGC checks the usage of the filesystem backing the
+// store's graph root against thresholdPercent, and if it's at or above the
+// threshold, prunes the least-recently-created dangling images (the same
+// set that "buildah rmi --prune" removes, respecting the store's usual
+// locking) one at a time until usage drops back below the threshold or
+// there's nothing left to prune, to try to make room before a build that's
+// likely to need it gets underway.
+func maybePruneForStorageGC(ctx context.Context, store storage.Store, systemContext *types.SystemContext, thresholdPercent uint) error {
+	if thresholdPercent == 0 {
+		return nil
+	}
+	usedPercent, err := storageUsedPercent(store.GraphRoot())
+	if err != nil {
+		logrus.Warnf("error checking storage usage for GC threshold: %v", err)
+		return nil
+	}
+	if usedPercent < uint64(thresholdPercent) {
+		return nil
+	}
+	logrus.Infof("storage at %s is %d%% full, at or above the %d%% GC threshold: pruning dangling images", store.GraphRoot(), usedPercent, thresholdPercent)
+
+	runtime, err := libimage.RuntimeFromStore(store, &libimage.RuntimeOptions{SystemContext: systemContext})
+	if err != nil {
+		return err
+	}
+	danglingImages, err := runtime.ListImages(ctx, nil, &libimage.ListImagesOptions{
+		Filters: []string{"readonly=false", "dangling=true"},
+	})
+	if err != nil {
+		return err
+	}
+	sort.Slice(danglingImages, func(i, j int) bool {
+		return danglingImages[i].Created().Before(danglingImages[j].Created())
+	})
+
+	for _, image := range danglingImages {
+		usedPercent, err = storageUsedPercent(store.GraphRoot())
+		if err != nil {
+			logrus.Warnf("error checking storage usage for GC threshold: %v", err)
+			return nil
+		}
+		if usedPercent < uint64(thresholdPercent) {
+			break
+		}
+		if _, rmErrors := runtime.RemoveImages(ctx, []string{image.ID()}, &libimage.RemoveImagesOptions{Filters: []string{"readonly=false"}}); len(rmErrors) != 0 {
+			for _, rmErr := range rmErrors {
+				logrus.Warnf("error pruning image %s during storage GC: %v", image.ID(), rmErr)
+			}
+			continue
+		}
+		logrus.Infof("pruned dangling image %s to make room", image.ID())
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+// +build !linux
+
+package main
+
+import (
+	"context"
+
+	"github.com/containers/image/v5/types"
+	"github.com/containers/storage"
+	"github.com/sirupsen/logrus"
+)
+
+// maybePruneForStorageGC is only implemented for Linux, where we know how
+// to check the backing filesystem's usage; elsewhere, a non-zero threshold
+// is silently ignored.
+func maybePruneForStorageGC(ctx context.Context, store storage.Store, systemContext *types.SystemContext, thresholdPercent uint) error {
+	if thresholdPercent != 0 {
+		logrus.Debugf("--storage-gc-threshold is not supported on this platform")
+	}
+	return nil
+}
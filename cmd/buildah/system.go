@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	buildah "github.com/containers/buildah"
+	"github.com/containers/buildah/pkg/formats"
+	"github.com/spf13/cobra"
+)
+
+type systemDfOptions struct {
+	format  string
+	json    bool
+	verbose bool
+}
+
+type systemDfSummaryParams struct {
+	Type        string
+	Total       int
+	Size        string
+	Reclaimable string
+}
+
+type systemDfImageParams struct {
+	ID          string
+	Size        string
+	UniqueSize  string
+	Reclaimable string
+}
+
+var systemDfSummaryHeader = map[string]string{
+	"Type":        "TYPE",
+	"Total":       "TOTAL",
+	"Size":        "SIZE",
+	"Reclaimable": "RECLAIMABLE",
+}
+
+var systemDfImageHeader = map[string]string{
+	"ID":          "IMAGE ID",
+	"Size":        "SIZE",
+	"UniqueSize":  "UNIQUE SIZE",
+	"Reclaimable": "RECLAIMABLE",
+}
+
+func init() {
+	var (
+		systemDescription   = "\n  Manages resources shared across buildah's other commands, such as disk usage."
+		systemDfDescription = "\n  Shows how much disk space is used by images, working containers, and layers in local storage, and how much of that could be reclaimed."
+		dfOpts              systemDfOptions
+	)
+	systemCommand := &cobra.Command{
+		Use:   "system",
+		Short: "Manage buildah's system resources",
+		Long:  systemDescription,
+		Example: `buildah system df
+  buildah system df --verbose`,
+	}
+	systemCommand.SetUsageTemplate(UsageTemplate())
+	rootCmd.AddCommand(systemCommand)
+
+	systemDfCommand := &cobra.Command{
+		Use:   "df",
+		Short: "Show disk usage of images, containers, and local storage",
+		Long:  systemDfDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return systemDfCmd(cmd, args, dfOpts)
+		},
+		Example: `buildah system df
+  buildah system df --verbose`,
+		Args: cobra.NoArgs,
+	}
+	systemDfCommand.SetUsageTemplate(UsageTemplate())
+
+	dfFlags := systemDfCommand.Flags()
+	dfFlags.StringVar(&dfOpts.format, "format", "", "pretty-print using a Go template")
+	dfFlags.BoolVar(&dfOpts.json, "json", false, "output in JSON format")
+	dfFlags.BoolVarP(&dfOpts.verbose, "verbose", "v", false, "show a per-image breakdown of disk usage")
+
+	systemCommand.AddCommand(systemDfCommand)
+}
+
+func systemDfCmd(c *cobra.Command, args []string, opts systemDfOptions) error {
+	store, err := getStore(c)
+	if err != nil {
+		return err
+	}
+
+	usage, err := buildah.GetDiskUsage(store)
+	if err != nil {
+		return err
+	}
+
+	if opts.json {
+		return formatSystemDfJSON(usage)
+	}
+
+	summary := []systemDfSummaryParams{
+		{Type: "Images", Total: len(usage.Images), Size: formattedSize(usage.ImagesSize), Reclaimable: formattedSize(usage.ImagesReclaimable)},
+		{Type: "Containers", Total: len(usage.Containers), Size: formattedSize(usage.ContainersSize), Reclaimable: formattedSize(0)},
+		{Type: "Local Layers", Total: len(usage.Layers), Size: formattedSize(usage.LayersSize), Reclaimable: formattedSize(0)},
+	}
+	summaryFormat := "table {{.Type}}\t{{.Total}}\t{{.Size}}\t{{.Reclaimable}}"
+	if opts.format != "" {
+		summaryFormat = opts.format
+	}
+	out := formats.StdoutTemplateArray{Output: systemDfSummaryToGeneric(summary), Template: summaryFormat, Fields: systemDfSummaryHeader}
+	if err := formats.Writer(out).Out(); err != nil {
+		return err
+	}
+
+	if !opts.verbose {
+		return nil
+	}
+
+	fmt.Println()
+	var images []systemDfImageParams
+	for _, image := range usage.Images {
+		images = append(images, systemDfImageParams{
+			ID:          truncateID(image.ID, true),
+			Size:        formattedSize(image.Size),
+			UniqueSize:  formattedSize(image.UniqueSize),
+			Reclaimable: fmt.Sprintf("%t", image.Reclaimable),
+		})
+	}
+	imagesOut := formats.StdoutTemplateArray{Output: systemDfImagesToGeneric(images), Template: "table {{.ID}}\t{{.Size}}\t{{.UniqueSize}}\t{{.Reclaimable}}", Fields: systemDfImageHeader}
+	return formats.Writer(imagesOut).Out()
+}
+
+func formatSystemDfJSON(usage *buildah.DiskUsage) error {
+	data, err := json.MarshalIndent(usage, "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", data)
+	return nil
+}
+
+func systemDfSummaryToGeneric(params []systemDfSummaryParams) (genericParams []interface{}) {
+	for _, v := range params {
+		genericParams = append(genericParams, interface{}(v))
+	}
+	return genericParams
+}
+
+func systemDfImagesToGeneric(params []systemDfImageParams) (genericParams []interface{}) {
+	for _, v := range params {
+		genericParams = append(genericParams, interface{}(v))
+	}
+	return genericParams
+}
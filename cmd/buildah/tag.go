@@ -1,27 +1,53 @@
 package main
 
 import (
+	"fmt"
+	"regexp"
+
 	"github.com/containers/buildah/pkg/parse"
 	"github.com/containers/common/libimage"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 )
 
-var (
-	tagDescription = "\n  Adds one or more additional names to locally-stored image."
-	tagCommand     = &cobra.Command{
+type tagOptions struct {
+	regex   string
+	replace string
+	dryRun  bool
+}
+
+func init() {
+	var (
+		opts           tagOptions
+		tagDescription = "\n  Adds one or more additional names to locally-stored image.  With --regex,\n  retags every locally-stored image whose name matches the pattern instead,\n  rather than requiring one invocation per image."
+	)
+	tagCommand := &cobra.Command{
 		Use:   "tag",
 		Short: "Add an additional name to a local image",
 		Long:  tagDescription,
-		RunE:  tagCmd,
-
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return tagCmd(cmd, args, opts)
+		},
 		Example: `buildah tag imageName firstNewName
-  buildah tag imageName firstNewName SecondNewName`,
-		Args: cobra.MinimumNArgs(2),
+  buildah tag imageName firstNewName SecondNewName
+  buildah tag --regex '^registry\.old/(.*)' --replace 'registry.new/$1'`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flag("regex").Changed {
+				return nil
+			}
+			return cobra.MinimumNArgs(2)(cmd, args)
+		},
 	}
-)
+	tagCommand.SetUsageTemplate(UsageTemplate())
+	flags := tagCommand.Flags()
+	flags.StringVar(&opts.regex, "regex", "", "match this `pattern` against the names of local images, and retag matches per --replace, instead of tagging a single named image")
+	flags.StringVar(&opts.replace, "replace", "", "with --regex, the replacement `pattern` for matched names, using $1-style references to capture groups")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "with --regex, print what would be retagged without actually tagging anything")
 
-func tagCmd(c *cobra.Command, args []string) error {
+	rootCmd.AddCommand(tagCommand)
+}
+
+func tagCmd(c *cobra.Command, args []string, iopts tagOptions) error {
 	store, err := getStore(c)
 	if err != nil {
 		return err
@@ -35,6 +61,10 @@ func tagCmd(c *cobra.Command, args []string) error {
 		return err
 	}
 
+	if iopts.regex != "" {
+		return retagMatching(runtime, args, iopts)
+	}
+
 	image, _, err := runtime.LookupImage(args[0], nil)
 	if err != nil {
 		return err
@@ -48,7 +78,39 @@ func tagCmd(c *cobra.Command, args []string) error {
 	return nil
 }
 
-func init() {
-	tagCommand.SetUsageTemplate(UsageTemplate())
-	rootCmd.AddCommand(tagCommand)
+// retagMatching implements "buildah tag --regex", applying a match/replace
+// pattern to the names of every local image (or, if names are given, only
+// those images) and tagging each image with the result whenever it differs
+// from the name that matched.
+func retagMatching(runtime *libimage.Runtime, names []string, iopts tagOptions) error {
+	pattern, err := regexp.Compile(iopts.regex)
+	if err != nil {
+		return errors.Wrapf(err, "error compiling --regex pattern %q", iopts.regex)
+	}
+
+	images, err := runtime.ListImages(getContext(), names, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, image := range images {
+		for _, name := range image.Names() {
+			if !pattern.MatchString(name) {
+				continue
+			}
+			newName := pattern.ReplaceAllString(name, iopts.replace)
+			if newName == name {
+				continue
+			}
+			if iopts.dryRun {
+				fmt.Printf("%s -> %s\n", name, newName)
+				continue
+			}
+			if err := image.Tag(newName); err != nil {
+				return errors.Wrapf(err, "error tagging %q as %q", name, newName)
+			}
+			fmt.Printf("%s -> %s\n", name, newName)
+		}
+	}
+	return nil
 }
@@ -82,7 +82,7 @@ func unshareMount(c *cobra.Command, mounts []string) ([]string, func(), error) {
 		logrus.Debugf("mounted container %q at %q", container, mountPoint)
 		mountedContainers = append(mountedContainers, container)
 		if envVar != "" {
-			envSpec := fmt.Sprintf("%s=%s", envVar, mountPoint)
+			envSpec := fmt.Sprintf("%s=%s", sanitizeEnvVarName(envVar), mountPoint)
 			logrus.Debugf("adding %q to environment", envSpec)
 			env = append(env, envSpec)
 		}
@@ -90,6 +90,26 @@ func unshareMount(c *cobra.Command, mounts []string) ([]string, func(), error) {
 	return env, unmount, nil
 }
 
+// sanitizeEnvVarName rewrites name, which may be a container name or ID given
+// as the default VARIABLE for a "--mount CTR" (without "=VAR") argument, into
+// a valid environment variable name by replacing any character that isn't a
+// letter, digit, or underscore with an underscore, and prefixing the result
+// with an underscore if it would otherwise start with a digit.
+func sanitizeEnvVarName(name string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+	if sanitized != "" && sanitized[0] >= '0' && sanitized[0] <= '9' {
+		sanitized = "_" + sanitized
+	}
+	return sanitized
+}
+
 // unshareCmd execs whatever using the ID mappings that we want to use for ourselves
 func unshareCmd(c *cobra.Command, args []string) error {
 	// Set the default isolation type to use the "rootless" method.
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/containers/buildah/pkg/parse"
+	"github.com/containers/common/pkg/auth"
+	"github.com/containers/image/v5/image"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/transports"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+type verifyOptions struct {
+	authfile  string
+	certDir   string
+	creds     string
+	tlsVerify bool
+	policy    string
+	format    string
+}
+
+// verifyResult is one IMAGE's outcome from "buildah verify".
+type verifyResult struct {
+	Image   string `json:"image"`
+	Allowed bool   `json:"allowed"`
+	Error   string `json:"error,omitempty"`
+}
+
+func init() {
+	var (
+		opts              verifyOptions
+		verifyDescription = `  Verifies one or more images against a signature policy, without pulling
+  or running them, and reports whether the policy allows each image to be
+  used.`
+	)
+	verifyCommand := &cobra.Command{
+		Use:   "verify IMAGE [IMAGE...]",
+		Short: "Verify an image against a signature policy",
+		Long:  verifyDescription,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return verifyCmd(cmd, args, opts)
+		},
+		Example:           `buildah verify registry.example.com/repository/image:tag`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeImages,
+	}
+	verifyCommand.SetUsageTemplate(UsageTemplate())
+	flags := verifyCommand.Flags()
+	flags.StringVar(&opts.authfile, "authfile", auth.GetDefaultAuthFile(), "path of the authentication file. Use REGISTRY_AUTH_FILE environment variable to override")
+	flags.StringVar(&opts.certDir, "cert-dir", "", "use certificates at the specified path to access the registry")
+	flags.StringVar(&opts.creds, "creds", "", "use `[username[:password]]` for accessing the registry")
+	flags.BoolVar(&opts.tlsVerify, "tls-verify", true, "require HTTPS and verify certificates when accessing the registry")
+	flags.StringVar(&opts.policy, "policy", "", "`pathname` of the signature policy file to verify against (default: the system-wide policy)")
+	flags.StringVar(&opts.format, "format", "", "alternate output `format`, one of \"json\"")
+
+	rootCmd.AddCommand(verifyCommand)
+}
+
+func verifyCmd(c *cobra.Command, args []string, iopts verifyOptions) error {
+	if err := auth.CheckAuthFile(iopts.authfile); err != nil {
+		return err
+	}
+
+	systemContext, err := parse.SystemContextFromOptions(c)
+	if err != nil {
+		return errors.Wrapf(err, "error building system context")
+	}
+
+	var policy *signature.Policy
+	if iopts.policy != "" {
+		policy, err = signature.NewPolicyFromFile(iopts.policy)
+	} else {
+		policy, err = signature.DefaultPolicy(systemContext)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "error loading signature policy")
+	}
+	policyContext, err := signature.NewPolicyContext(policy)
+	if err != nil {
+		return errors.Wrapf(err, "error creating signature policy context")
+	}
+	defer func() {
+		if err := policyContext.Destroy(); err != nil {
+			logrus.Debugf("error destroying signature policy context: %v", err)
+		}
+	}()
+
+	ctx := getContext()
+	results := make([]verifyResult, 0, len(args))
+	allowedCount := 0
+	for _, imageSpec := range args {
+		result := verifyResult{Image: imageSpec}
+		ref, err := resolveDockerReference(imageSpec)
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.Image = transports.ImageName(ref)
+
+		src, err := ref.NewImageSource(ctx, systemContext)
+		if err != nil {
+			result.Error = errors.Wrapf(err, "error opening image %q", result.Image).Error()
+			results = append(results, result)
+			continue
+		}
+		unparsedImage := image.UnparsedInstance(src, nil)
+		allowed, err := policyContext.IsRunningImageAllowed(ctx, unparsedImage)
+		if err := src.Close(); err != nil {
+			logrus.Debugf("error closing image source for %q: %v", result.Image, err)
+		}
+		result.Allowed = allowed
+		if err != nil {
+			result.Error = err.Error()
+		}
+		if result.Allowed {
+			allowedCount++
+		}
+		results = append(results, result)
+	}
+
+	if iopts.format == "json" {
+		data, err := json.MarshalIndent(results, "", "    ")
+		if err != nil {
+			return errors.Wrapf(err, "error formatting verification results")
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, result := range results {
+			if result.Error != "" {
+				fmt.Printf("%s: rejected: %s\n", result.Image, result.Error)
+				continue
+			}
+			if result.Allowed {
+				fmt.Printf("%s: allowed\n", result.Image)
+			} else {
+				fmt.Printf("%s: rejected\n", result.Image)
+			}
+		}
+	}
+
+	if allowedCount != len(results) {
+		return errors.Errorf("%d of %d images did not pass verification", len(results)-allowedCount, len(results))
+	}
+	return nil
+}
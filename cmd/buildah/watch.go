@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containers/buildah/define"
+	"github.com/containers/buildah/imagebuildah"
+	"github.com/containers/storage"
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// watchDebounce is how long we wait after the last observed filesystem event
+// before triggering a rebuild, so that a burst of writes (e.g., an editor
+// saving several files, or a "git checkout") only causes one rebuild.
+const watchDebounce = 300 * time.Millisecond
+
+// watchAndBuild builds the image once, then watches the build context for
+// changes and rebuilds, reusing the build cache, each time the context is
+// modified.  It runs until the context is canceled.
+func watchAndBuild(ctx context.Context, store storage.Store, options define.BuildOptions, dockerfiles []string, contextDir string) error {
+	build := func() {
+		imageID, _, err := imagebuildah.BuildDockerfiles(ctx, store, options, dockerfiles...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "build failed: %v\n", err)
+			return
+		}
+		fmt.Printf("built %s\n", imageID)
+	}
+	build()
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.Wrapf(err, "error creating filesystem watcher")
+	}
+	defer watcher.Close()
+	if err := addWatchesRecursively(watcher, contextDir); err != nil {
+		return errors.Wrapf(err, "error watching build context %q", contextDir)
+	}
+
+	logrus.Infof("watching %q for changes; press Ctrl-C to stop", contextDir)
+
+	debounce := time.NewTimer(time.Hour)
+	if !debounce.Stop() {
+		<-debounce.C
+	}
+	pending := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&fsnotify.Create == fsnotify.Create {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					if err := addWatchesRecursively(watcher, event.Name); err != nil {
+						logrus.Warnf("error watching new directory %q: %v", event.Name, err)
+					}
+				}
+			}
+			fmt.Printf("change: %s\n", event.Name)
+			if !pending {
+				pending = true
+				debounce.Reset(watchDebounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logrus.Errorf("error watching build context: %v", err)
+		case <-debounce.C:
+			pending = false
+			fmt.Printf("rebuilding %q\n", contextDir)
+			build()
+		}
+	}
+}
+
+// addWatchesRecursively adds a watch for dir and every directory beneath it,
+// since fsnotify only watches the directories it's explicitly told about.
+func addWatchesRecursively(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
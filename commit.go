@@ -6,10 +6,13 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/containers/buildah/define"
 	"github.com/containers/buildah/pkg/blobcache"
+	"github.com/containers/buildah/pkg/chrootuser"
 	"github.com/containers/buildah/util"
 	"github.com/containers/common/libimage"
 	"github.com/containers/common/libimage/manifests"
@@ -19,6 +22,7 @@ import (
 	"github.com/containers/image/v5/signature"
 	is "github.com/containers/image/v5/storage"
 	"github.com/containers/image/v5/transports"
+	"github.com/containers/image/v5/transports/alltransports"
 	"github.com/containers/image/v5/types"
 	encconfig "github.com/containers/ocicrypt/config"
 	"github.com/containers/storage"
@@ -34,6 +38,11 @@ const (
 	// the name and version of the producer of the image stored as an
 	// annotation on commit.
 	BuilderIdentityAnnotation = "io.buildah.version"
+	// BuilderBaseImageSourceAnnotation is the name of the annotation key
+	// used to record the fully-qualified reference which actually served
+	// the base image, in case a mirror or alternate search registry was
+	// used instead of the primary source named on the command line.
+	BuilderBaseImageSourceAnnotation = "io.buildah.base-image-source"
 )
 
 // CommitOptions can be used to alter how an image is committed.
@@ -58,6 +67,10 @@ type CommitOptions struct {
 	// ReportWriter is an io.Writer which will be used to log the writing
 	// of the new image.
 	ReportWriter io.Writer
+	// Progress, if set, receives structured notifications of the progress
+	// of copying blobs while writing the new image, in addition to
+	// whatever is written to ReportWriter.
+	Progress define.ProgressReporter
 	// HistoryTimestamp is the timestamp used when creating new items in the
 	// image's history.  If unset, the current time will be used.
 	HistoryTimestamp *time.Time
@@ -101,6 +114,12 @@ type CommitOptions struct {
 	// integers in the slice represent 0-indexed layer indices, with support for negative
 	// indexing. i.e. 0 is the first layer, -1 is the last (top-most) layer.
 	OciEncryptLayers *[]int
+	// Validate, if set, checks that the image's configured USER exists in
+	// the image's /etc/passwd, and warns if the configured WORKDIR or the
+	// binary named by ENTRYPOINT/CMD is missing from the image, before the
+	// image is committed.  This catches the most common "image builds but
+	// won't start" mistakes.
+	Validate bool
 }
 
 var (
@@ -238,6 +257,11 @@ func (b *Builder) Commit(ctx context.Context, dest types.ImageReference, options
 		timestamp := time.Unix(0, 0).UTC()
 		options.HistoryTimestamp = &timestamp
 	}
+	if options.Validate {
+		if err := b.validateImageConfiguration(); err != nil {
+			return imgID, nil, "", err
+		}
+	}
 	nameToRemove := ""
 	if dest == nil {
 		nameToRemove = stringid.GenerateRandomID() + "-tmp"
@@ -332,7 +356,15 @@ func (b *Builder) Commit(ctx context.Context, dest types.ImageReference, options
 	}
 
 	var manifestBytes []byte
-	if manifestBytes, err = retryCopyImage(ctx, policyContext, maybeCachedDest, maybeCachedSrc, dest, getCopyOptions(b.store, options.ReportWriter, nil, systemContext, "", false, options.SignBy, options.OciEncryptLayers, options.OciEncryptConfig, nil), options.MaxRetries, options.RetryDelay); err != nil {
+	copyOptions := getCopyOptions(b.store, options.ReportWriter, nil, systemContext, "", false, options.SignBy, options.OciEncryptLayers, options.OciEncryptConfig, nil)
+	progressChan, stopProgress := startBlobProgress(options.Progress)
+	copyOptions.Progress = progressChan
+	if progressChan != nil {
+		copyOptions.ProgressInterval = time.Second
+	}
+	manifestBytes, err = retryCopyImage(ctx, policyContext, maybeCachedDest, maybeCachedSrc, dest, copyOptions, options.MaxRetries, options.RetryDelay)
+	stopProgress()
+	if err != nil {
 		return imgID, nil, "", errors.Wrapf(err, "error copying layers and metadata for container %q", b.ContainerID)
 	}
 	// If we've got more names to attach, and we know how to do that for
@@ -406,3 +438,94 @@ func (b *Builder) Commit(ctx context.Context, dest types.ImageReference, options
 	}
 	return imgID, ref, manifestDigest, nil
 }
+
+// CommitToWriter writes a new image, in "docker-archive" format (or
+// "oci-archive" format, if options.PreferredManifestType is
+// define.OCIv1ImageManifest), as a tar stream to writer, without requiring
+// that the image ever be given a name or otherwise land in local container
+// storage.  Aside from the destination, it behaves the same as Commit.
+//
+// Under the hood, the image is still assembled using a temporary archive
+// file, since the transports that produce container image archives need to
+// be able to seek within the file they're writing; that temporary file is
+// removed, and never made visible to the caller, before this function
+// returns.
+func (b *Builder) CommitToWriter(ctx context.Context, writer io.Writer, options CommitOptions) (string, reference.Canonical, digest.Digest, error) {
+	transportName := "docker-archive"
+	if options.PreferredManifestType == define.OCIv1ImageManifest {
+		transportName = "oci-archive"
+	}
+	archiveFile, err := ioutil.TempFile(os.TempDir(), define.Package+"-commit")
+	if err != nil {
+		return "", nil, "", errors.Wrap(err, "error creating temporary file for image archive")
+	}
+	archivePath := archiveFile.Name()
+	defer os.Remove(archivePath)
+	if err := archiveFile.Close(); err != nil {
+		return "", nil, "", errors.Wrap(err, "error creating temporary file for image archive")
+	}
+
+	dest, err := alltransports.ParseImageName(transportName + ":" + archivePath)
+	if err != nil {
+		return "", nil, "", errors.Wrapf(err, "error parsing %s: as an image reference", transportName)
+	}
+
+	imgID, ref, manifestDigest, err := b.Commit(ctx, dest, options)
+	if err != nil {
+		return imgID, ref, manifestDigest, err
+	}
+
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		return imgID, ref, manifestDigest, errors.Wrap(err, "error opening image archive to copy it to writer")
+	}
+	defer archive.Close()
+	if _, err := io.Copy(writer, archive); err != nil {
+		return imgID, ref, manifestDigest, errors.Wrap(err, "error copying image archive to writer")
+	}
+	return imgID, ref, manifestDigest, nil
+}
+
+// validateImageConfiguration mounts the container and checks its
+// configuration for the most common "image builds but won't start"
+// mistakes: a USER that isn't in /etc/passwd, a WORKDIR that doesn't exist,
+// and an ENTRYPOINT/CMD binary (when given as an absolute path) that either
+// doesn't exist or isn't executable.  A missing WORKDIR or entry point is
+// only logged as a warning, since container engines commonly create the
+// former and some entry points are resolved from $PATH at run time.
+func (b *Builder) validateImageConfiguration() error {
+	mountPoint, err := b.Mount(b.MountLabel)
+	if err != nil {
+		return errors.Wrapf(err, "error mounting container %q for configuration validation", b.ContainerID)
+	}
+	defer func() {
+		if err := b.Unmount(); err != nil {
+			logrus.Warnf("error unmounting container %q after configuration validation: %v", b.ContainerID, err)
+		}
+	}()
+
+	if user := b.User(); user != "" {
+		if _, _, _, err := chrootuser.GetUser(mountPoint, user); err != nil {
+			return errors.Wrapf(err, "configured USER %q not found in image", user)
+		}
+	}
+
+	if workDir := b.WorkDir(); workDir != "" {
+		if info, err := os.Stat(filepath.Join(mountPoint, workDir)); err != nil || !info.IsDir() {
+			logrus.Warnf("configured WORKDIR %q does not exist in the image", workDir)
+		}
+	}
+
+	entryPoint := append(append([]string{}, b.Entrypoint()...), b.Cmd()...)
+	if len(entryPoint) > 0 && filepath.IsAbs(entryPoint[0]) {
+		info, err := os.Stat(filepath.Join(mountPoint, entryPoint[0]))
+		switch {
+		case err != nil:
+			logrus.Warnf("configured ENTRYPOINT/CMD binary %q does not exist in the image", entryPoint[0])
+		case info.Mode()&0111 == 0:
+			logrus.Warnf("configured ENTRYPOINT/CMD binary %q is not executable", entryPoint[0])
+		}
+	}
+
+	return nil
+}
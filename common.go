@@ -48,6 +48,31 @@ func getCopyOptions(store storage.Store, reportWriter io.Writer, sourceSystemCon
 	}
 }
 
+// startBlobProgress, if progress is non-nil, starts a goroutine which reads
+// types.ProgressProperties values from a freshly-created channel and passes
+// each one along to progress's BlobProgress method, and returns the channel
+// for the caller to hand to a copy.Options or libimage CopyOptions, along
+// with a function that the caller should call after the copy operation has
+// finished, to shut the goroutine down again.  If progress is nil, it
+// returns a nil channel and a no-op cleanup function.
+func startBlobProgress(progress define.ProgressReporter) (chan types.ProgressProperties, func()) {
+	if progress == nil {
+		return nil, func() {}
+	}
+	progressChan := make(chan types.ProgressProperties)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for properties := range progressChan {
+			progress.BlobProgress(properties)
+		}
+	}()
+	return progressChan, func() {
+		close(progressChan)
+		<-done
+	}
+}
+
 func getSystemContext(store storage.Store, defaults *types.SystemContext, signaturePolicyPath string) *types.SystemContext {
 	sc := &types.SystemContext{}
 	if defaults != nil {
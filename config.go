@@ -10,6 +10,7 @@ import (
 
 	"github.com/containers/buildah/define"
 	"github.com/containers/buildah/docker"
+	"github.com/containers/buildah/util"
 	"github.com/containers/image/v5/manifest"
 	"github.com/containers/image/v5/transports"
 	"github.com/containers/image/v5/types"
@@ -189,6 +190,55 @@ func (b *Builder) SetArchitecture(arch string) {
 	b.Docker.Architecture = arch
 }
 
+// OSVersion returns a version of the OS on which the container, or a
+// container built using an image built from this container, is intended to
+// be run, if it's been set.
+func (b *Builder) OSVersion() string {
+	return b.Docker.OSVersion
+}
+
+// SetOSVersion sets the version of the OS on which the container, or a
+// container built using an image built from this container, is intended to
+// be run.
+func (b *Builder) SetOSVersion(version string) {
+	b.Docker.OSVersion = version
+}
+
+// OSFeatures returns the list of features that the OS on which the
+// container, or a container built using an image built from this
+// container, is intended to be run needs to support, if any are set.
+func (b *Builder) OSFeatures() []string {
+	return append([]string{}, b.Docker.OSFeatures...)
+}
+
+// SetOSFeature adds a feature of the OS which an image built from this
+// container will require to be present in order to run.
+func (b *Builder) SetOSFeature(feature string) {
+	if !util.StringInSlice(feature, b.Docker.OSFeatures) {
+		b.Docker.OSFeatures = append(b.Docker.OSFeatures, feature)
+	}
+}
+
+// UnsetOSFeature removes a feature of the OS from the set that an image
+// built from this container will require to be present in order to run.
+func (b *Builder) UnsetOSFeature(feature string) {
+	if util.StringInSlice(feature, b.Docker.OSFeatures) {
+		removed := make([]string, 0, len(b.Docker.OSFeatures))
+		for _, f := range b.Docker.OSFeatures {
+			if f != feature {
+				removed = append(removed, f)
+			}
+		}
+		b.Docker.OSFeatures = removed
+	}
+}
+
+// ClearOSFeatures removes all of the OS features which have been added to
+// this container's list of requirements.
+func (b *Builder) ClearOSFeatures() {
+	b.Docker.OSFeatures = []string{}
+}
+
 // Maintainer returns contact information for the person who built the image.
 func (b *Builder) Maintainer() string {
 	return b.OCIv1.Author
@@ -9,6 +9,58 @@ import (
 	"github.com/containers/storage/pkg/archive"
 )
 
+// ProgressReporter can be supplied to receive structured notifications about
+// a build's progress, as an alternative (or supplement) to the human-readable
+// text that's written to ReportWriter.  Implementations should return
+// quickly, since the calls are made synchronously from the code performing
+// the corresponding work.
+type ProgressReporter interface {
+	// StepStarted is called just before a Dockerfile instruction begins
+	// executing.  index is 0-based; total is the number of instructions
+	// in the stage being built.
+	StepStarted(index, total int, message string)
+	// StepFinished is called once a Dockerfile instruction has finished
+	// executing, whether or not it was satisfied by the build cache.
+	StepFinished(index, total int, cacheHit bool)
+	// BlobProgress is called while a blob is being pulled or pushed as
+	// part of satisfying a FROM instruction or writing the resulting
+	// image, with the same properties that would otherwise only be sent
+	// to a Progress channel handed to the containers/image library.
+	BlobProgress(properties types.ProgressProperties)
+}
+
+// InstructionHookParams carries the information which is passed to an
+// InstructionHook about the Dockerfile instruction it's being called for.
+type InstructionHookParams struct {
+	// Stage is the name of the stage which the instruction belongs to,
+	// as given in an AS clause, or its 0-based index formatted as a
+	// string if it wasn't named.
+	Stage string
+	// StepIndex and StepCount are the instruction's 0-based position
+	// among, and the number of, instructions in its stage.
+	StepIndex, StepCount int
+	// Instruction is the instruction's source text, as it appeared in
+	// the Dockerfile.
+	Instruction string
+	// ContainerID is the ID of the build container that the instruction
+	// is being run against.
+	ContainerID string
+}
+
+// InstructionHook, if set, is called immediately before and immediately
+// after each Dockerfile instruction is executed, letting a caller embedding
+// this library enforce custom policies (e.g., forbidding certain RUN
+// commands), record timing information, or otherwise observe the build as
+// it progresses.  It's called with before set to true just before the
+// instruction runs, and again with before set to false once it's finished;
+// returning an error from the "before" call aborts the build without
+// running the instruction.  The return value of the "after" call is
+// ignored.  ContainerID, rather than a *buildah.Builder, is passed in
+// InstructionHookParams to avoid a circular dependency between this
+// package and the top-level buildah package; buildah.OpenBuilder() can be
+// used to look up the Builder if one is needed.
+type InstructionHook func(before bool, params InstructionHookParams) error
+
 // CommonBuildOptions are resources that can be defined by flags for both buildah from and build-using-dockerfile
 type CommonBuildOptions struct {
 	// AddHost is the list of hostnames to add to the build container's /etc/hosts.
@@ -47,6 +99,16 @@ type CommonBuildOptions struct {
 	SeccompProfilePath string
 	// ApparmorProfile is the name of an apparmor profile.
 	ApparmorProfile string
+	// ReadOnly causes the container's root filesystem to be mounted
+	// read-only for every RUN step, with tmpfs mounts automatically
+	// added over /tmp, /run, and /var/tmp.
+	ReadOnly bool
+	// AddHostContainersInternal controls whether or not a "host.containers.internal"
+	// entry, resolving to the rootless networking gateway address, is added to
+	// /etc/hosts for every RUN step, so that steps which need to reach a
+	// service (such as a registry) bound to localhost on the build host don't
+	// need to be rewritten to know about slirp4netns or pasta.
+	AddHostContainersInternal bool
 	// ShmSize is the "size" value to use when mounting an shmfs on the container's /dev/shm directory.
 	ShmSize string
 	// Ulimit specifies resource limit options, in the form type:softlimit[:hardlimit].
@@ -133,6 +195,12 @@ type BuildOptions struct {
 	// progress of the (possible) pulling of the source image and the
 	// writing of the new image.
 	ReportWriter io.Writer
+	// Progress, if set, receives structured notifications of build
+	// progress in addition to whatever is written to ReportWriter.
+	Progress ProgressReporter
+	// InstructionHook, if set, is called before and after each
+	// Dockerfile instruction is executed.
+	InstructionHook InstructionHook
 	// OutputFormat is the format of the output image's manifest and
 	// configuration data.
 	// Accepted values are buildah.OCIv1ImageManifest and buildah.Dockerv2ImageManifest.
@@ -181,6 +249,13 @@ type BuildOptions struct {
 	OnBuild []string
 	// Layers tells the builder to create a cache of images for each step in the Dockerfile
 	Layers bool
+	// CacheBackend, if not empty, is the name of a CacheBackend registered
+	// with RegisterCacheBackend (or "local", which is always available and
+	// looks for and records cached intermediate images in local container
+	// storage) that intermediate build results should also be looked up in
+	// and recorded to, alongside the search of local container storage that
+	// Layers already performs.
+	CacheBackend string
 	// NoCache tells the builder to build the image from scratch without checking for a cache.
 	// It creates a new set of cached images for the build.
 	NoCache bool
@@ -224,4 +299,21 @@ type BuildOptions struct {
 	// From is the image name to use to replace the value specified in the first
 	// FROM instruction in the Containerfile
 	From string
+	// RequirePrimarySource causes the build to fail instead of silently
+	// falling back to a mirror or alternate search registry for a base
+	// image named in a FROM instruction.
+	RequirePrimarySource bool
+	// MaxBaseAge, if non-zero, is the oldest that a FROM image is allowed
+	// to be, measured from the time it was pulled into local storage, before
+	// BaseAgePolicy is applied.
+	MaxBaseAge time.Duration
+	// BaseAgePolicy controls what happens when a FROM image's age exceeds
+	// MaxBaseAge.  It should be one of BaseAgeWarn or BaseAgeFail.
+	BaseAgePolicy BaseAgePolicy
+	// StageEnv maps a stage's name (or, for anonymous stages, its numeric
+	// position, as text) to a set of environment variables which should be
+	// set only for the RUN steps of that stage, so that toolchain-only
+	// settings don't leak into the environment, or cache keys, of stages
+	// which don't request them.
+	StageEnv map[string]map[string]string
 }
@@ -0,0 +1,55 @@
+package define
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// CacheBackend is implemented by types which can look up and record the
+// mapping from a build step's cache key to the ID of the image that running
+// that step previously produced, letting callers plug in alternate places to
+// look for or store cached intermediate images, instead of relying solely on
+// images committed to local container storage.
+type CacheBackend interface {
+	// Lookup returns the ID of the image which was previously stored
+	// under key, or an empty string if the backend doesn't have one.
+	Lookup(ctx context.Context, key string) (string, error)
+	// Store records that key produced the image with the given ID, so
+	// that a later Lookup with the same key can find it.
+	Store(ctx context.Context, key, imageID string) error
+}
+
+var (
+	cacheBackendsMutex sync.Mutex
+	cacheBackends      = make(map[string]CacheBackend)
+)
+
+// RegisterCacheBackend registers a CacheBackend under the given name, so
+// that it can be selected later by setting BuildOptions.CacheBackend to the
+// same name.  It's intended to be called from the init() function of a
+// package which implements a custom cache backend (e.g., one backed by S3 or
+// GCS).  It panics if a backend is already registered under name, in the
+// same way that image transports and drivers in other container libraries
+// reject duplicate registrations.
+func RegisterCacheBackend(name string, backend CacheBackend) {
+	cacheBackendsMutex.Lock()
+	defer cacheBackendsMutex.Unlock()
+	if _, taken := cacheBackends[name]; taken {
+		panic("duplicate cache backend name " + name)
+	}
+	cacheBackends[name] = backend
+}
+
+// GetCacheBackend returns the CacheBackend which was registered under name
+// using RegisterCacheBackend.
+func GetCacheBackend(name string) (CacheBackend, error) {
+	cacheBackendsMutex.Lock()
+	defer cacheBackendsMutex.Unlock()
+	backend, ok := cacheBackends[name]
+	if !ok {
+		return nil, errors.Errorf("no cache backend is registered under the name %q", name)
+	}
+	return backend, nil
+}
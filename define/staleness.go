@@ -0,0 +1,37 @@
+package define
+
+import (
+	"fmt"
+)
+
+// BaseAgePolicy takes the value BaseAgeWarn or BaseAgeFail, controlling what
+// happens when a FROM image is older than BuildOptions.MaxBaseAge allows.
+type BaseAgePolicy int
+
+const (
+	// BaseAgeWarn is one of the values that BuildOptions.BaseAgePolicy can
+	// take, signalling that the build should log a warning and continue
+	// when a FROM image is too old.
+	BaseAgeWarn BaseAgePolicy = iota
+	// BaseAgeFail is one of the values that BuildOptions.BaseAgePolicy can
+	// take, signalling that the build should fail when a FROM image is too
+	// old.
+	BaseAgeFail
+)
+
+// String converts a BaseAgePolicy into a string.
+func (b BaseAgePolicy) String() string {
+	switch b {
+	case BaseAgeWarn:
+		return "warn"
+	case BaseAgeFail:
+		return "fail"
+	}
+	return fmt.Sprintf("unrecognized base image age policy %d", b)
+}
+
+// BaseAgePolicyMap maps names of policies to their values.
+var BaseAgePolicyMap = map[string]BaseAgePolicy{
+	"warn": BaseAgeWarn,
+	"fail": BaseAgeFail,
+}
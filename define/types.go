@@ -90,6 +90,15 @@ type IDMappingOptions struct {
 	HostGIDMapping bool
 	UIDMap         []specs.LinuxIDMapping
 	GIDMap         []specs.LinuxIDMapping
+	// AutoUserNs indicates that a container-specific range of host UIDs
+	// and GIDs should be allocated automatically by the storage library
+	// instead of using UIDMap/GIDMap, so that concurrent builds don't
+	// need manually-managed, non-overlapping mappings.
+	AutoUserNs bool
+	// AutoUserNsSize is the size of the automatically allocated user
+	// namespace, if AutoUserNs is set.  If zero, the storage library
+	// picks a size based on the image being used.
+	AutoUserNsSize uint32
 }
 
 // TempDirForURL checks if the passed-in string looks like a URL or -.  If it is,
@@ -0,0 +1,46 @@
+package buildah
+
+import (
+	"sort"
+
+	"github.com/containers/storage"
+	"github.com/containers/storage/pkg/archive"
+)
+
+// DiffEntry describes a single path that was added, changed, or removed
+// between two layers, as returned by GetLayerDiff.  Size is the size of an
+// added or modified regular file; it's zero for deleted paths and for
+// directories, symlinks, and other non-regular files.
+type DiffEntry struct {
+	Path string
+	Kind archive.ChangeType
+	Size int64
+}
+
+// GetLayerDiff lists the paths that were added, changed, or removed between
+// the "from" and "to" layers, with the resulting size of each added or
+// modified regular file, so that callers (for example, "buildah diff")
+// don't need to walk the tar diff themselves.
+func GetLayerDiff(store storage.Store, from, to string) ([]DiffEntry, error) {
+	changes, err := store.Changes(from, to)
+	if err != nil {
+		return nil, err
+	}
+	sizes, err := diffFileSizes(store, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]DiffEntry, 0, len(changes))
+	for _, change := range changes {
+		var size int64
+		if change.Kind != archive.ChangeDelete {
+			size = sizes[change.Path]
+		}
+		entries = append(entries, DiffEntry{Path: change.Path, Kind: change.Kind, Size: size})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Path < entries[j].Path
+	})
+	return entries, nil
+}
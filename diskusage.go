@@ -0,0 +1,159 @@
+package buildah
+
+import (
+	"github.com/containers/storage"
+)
+
+// LayerDiskUsage summarizes a single layer's on-disk size, and whether any
+// other image or working container in the store shares it, as returned as
+// part of a DiskUsage by GetDiskUsage.
+type LayerDiskUsage struct {
+	ID     string
+	Size   int64
+	Shared bool
+}
+
+// ImageDiskUsage summarizes an image's on-disk usage: the size of all of
+// the layers that make it up, and the portion of that size ("unique size")
+// which isn't shared with any other image or working container, and so
+// would actually be freed if the image were removed.
+type ImageDiskUsage struct {
+	ID          string
+	Size        int64
+	UniqueSize  int64
+	Reclaimable bool
+}
+
+// ContainerDiskUsage summarizes a working container's on-disk usage: the
+// size of the read-write layer it's added on top of its base image.
+type ContainerDiskUsage struct {
+	ID   string
+	Size int64
+}
+
+// DiskUsage is a per-category and grand-total accounting of the disk space
+// used by a storage.Store's images, working containers, and layers, as
+// returned by GetDiskUsage.  It doesn't have a category for RUN
+// --mount=type=cache mounts, since this version of buildah doesn't
+// implement that mount type.
+type DiskUsage struct {
+	Images     []ImageDiskUsage
+	Containers []ContainerDiskUsage
+	Layers     []LayerDiskUsage
+
+	ImagesSize        int64
+	ImagesReclaimable int64
+	ContainersSize    int64
+	LayersSize        int64
+}
+
+// GetDiskUsage walks the store's layer graph to report, per image, working
+// container, and layer, how much disk space is used, and how much of an
+// image's space is unique to it (and so reclaimable by removing it) versus
+// shared with other images or containers.
+func GetDiskUsage(store storage.Store) (*DiskUsage, error) {
+	images, err := store.Images()
+	if err != nil {
+		return nil, err
+	}
+	containers, err := store.Containers()
+	if err != nil {
+		return nil, err
+	}
+	layers, err := store.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	layerByID := make(map[string]storage.Layer, len(layers))
+	for _, layer := range layers {
+		layerByID[layer.ID] = layer
+	}
+
+	// refCount tallies how many images' and containers' layers include a
+	// given layer, so that its size can be attributed as "shared" once it's
+	// claimed by more than one of them.
+	refCount := make(map[string]int, len(layers))
+	imageChains := make(map[string][]string, len(images))
+	for _, image := range images {
+		chain := layerChain(layerByID, image.TopLayer)
+		imageChains[image.ID] = chain
+		for _, id := range chain {
+			refCount[id]++
+		}
+	}
+	for _, container := range containers {
+		if container.LayerID != "" {
+			refCount[container.LayerID]++
+		}
+	}
+
+	du := &DiskUsage{}
+	for _, layer := range layers {
+		size := layerSize(layer)
+		du.Layers = append(du.Layers, LayerDiskUsage{
+			ID:     layer.ID,
+			Size:   size,
+			Shared: refCount[layer.ID] > 1,
+		})
+		du.LayersSize += size
+	}
+
+	for _, image := range images {
+		var size, unique int64
+		for _, id := range imageChains[image.ID] {
+			layerSize := layerSize(layerByID[id])
+			size += layerSize
+			if refCount[id] <= 1 {
+				unique += layerSize
+			}
+		}
+		reclaimable := len(image.Names) == 0
+		du.Images = append(du.Images, ImageDiskUsage{
+			ID:          image.ID,
+			Size:        size,
+			UniqueSize:  unique,
+			Reclaimable: reclaimable,
+		})
+		du.ImagesSize += size
+		if reclaimable {
+			du.ImagesReclaimable += unique
+		}
+	}
+
+	for _, container := range containers {
+		size := layerSize(layerByID[container.LayerID])
+		du.Containers = append(du.Containers, ContainerDiskUsage{
+			ID:   container.ID,
+			Size: size,
+		})
+		du.ContainersSize += size
+	}
+
+	return du, nil
+}
+
+// layerChain returns the IDs of topLayer and each of its ancestors, in
+// order from topLayer down to the layer with no parent.
+func layerChain(layerByID map[string]storage.Layer, topLayer string) []string {
+	var chain []string
+	id := topLayer
+	for id != "" {
+		chain = append(chain, id)
+		layer, ok := layerByID[id]
+		if !ok {
+			break
+		}
+		id = layer.Parent
+	}
+	return chain
+}
+
+// layerSize returns a layer's uncompressed size, or 0 if it hasn't been
+// recorded, since UncompressedSize is -1 for layers where it isn't known.
+func layerSize(layer storage.Layer) int64 {
+	if layer.UncompressedSize < 0 {
+		return 0
+	}
+	return layer.UncompressedSize
+}
@@ -0,0 +1,65 @@
+package imagebuildah
+
+import (
+	"context"
+
+	"github.com/containers/buildah/define"
+	"github.com/containers/storage"
+	digest "github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+)
+
+// localCacheBackendName is the name reserved for the CacheBackend which
+// looks for and records cached intermediate images in local container
+// storage.  Unlike other backends, it doesn't need to be registered with
+// define.RegisterCacheBackend, since it's always available and tied to the
+// store that the Executor is already using.
+const localCacheBackendName = "local"
+
+// localStorageCacheBackend is a define.CacheBackend which records cache
+// key -> image ID mappings by giving the image an additional, synthetic
+// name derived from its cache key, so that a later Lookup can find it again
+// by that name in local container storage.
+type localStorageCacheBackend struct {
+	store storage.Store
+}
+
+// newLocalStorageCacheBackend returns a define.CacheBackend which looks for
+// and records intermediate images in store.
+func newLocalStorageCacheBackend(store storage.Store) define.CacheBackend {
+	return &localStorageCacheBackend{store: store}
+}
+
+// cacheName turns a cache key into a name that's suitable for use as an
+// image name in local container storage.
+func (l *localStorageCacheBackend) cacheName(key string) string {
+	return "localhost/buildah-cache-backend:" + digest.FromString(key).Encoded()
+}
+
+func (l *localStorageCacheBackend) Lookup(ctx context.Context, key string) (string, error) {
+	image, err := l.store.Image(l.cacheName(key))
+	if err != nil {
+		if errors.Cause(err) == storage.ErrImageUnknown {
+			return "", nil
+		}
+		return "", err
+	}
+	return image.ID, nil
+}
+
+func (l *localStorageCacheBackend) Store(ctx context.Context, key, imageID string) error {
+	cacheName := l.cacheName(key)
+	names, err := l.store.Names(imageID)
+	if err != nil {
+		return errors.Wrapf(err, "error reading names of image %q for cache backend", imageID)
+	}
+	for _, name := range names {
+		if name == cacheName {
+			return nil
+		}
+	}
+	if err := l.store.SetNames(imageID, append(names, cacheName)); err != nil {
+		return errors.Wrapf(err, "error recording cache backend name %q for image %q", cacheName, imageID)
+	}
+	return nil
+}
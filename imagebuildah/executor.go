@@ -110,6 +110,10 @@ type Executor struct {
 	maxPullPushRetries             int
 	retryPullPushDelay             time.Duration
 	ociDecryptConfig               *encconfig.DecryptConfig
+	requirePrimarySource           bool
+	maxBaseAge                     time.Duration
+	baseAgePolicy                  define.BaseAgePolicy
+	stageEnv                       map[string]map[string]string
 	lastError                      error
 	terminatedStage                map[string]struct{}
 	stagesLock                     sync.Mutex
@@ -122,6 +126,9 @@ type Executor struct {
 	fromOverride                   string
 	manifest                       string
 	secrets                        map[string]string
+	progress                       define.ProgressReporter
+	instructionHook                define.InstructionHook
+	cacheBackend                   define.CacheBackend
 }
 
 type imageTypeAndHistoryAndDiffIDs struct {
@@ -197,6 +204,18 @@ func NewExecutor(logger *logrus.Logger, store storage.Store, options define.Buil
 		}
 	}
 
+	var cacheBackend define.CacheBackend
+	switch options.CacheBackend {
+	case "":
+		// No additional cache backend was requested.
+	case localCacheBackendName:
+		cacheBackend = newLocalStorageCacheBackend(store)
+	default:
+		if cacheBackend, err = define.GetCacheBackend(options.CacheBackend); err != nil {
+			return nil, err
+		}
+	}
+
 	exec := Executor{
 		logger:                         logger,
 		stages:                         make(map[string]*StageExecutor),
@@ -221,6 +240,9 @@ func NewExecutor(logger *logrus.Logger, store storage.Store, options define.Buil
 		out:                            options.Out,
 		err:                            options.Err,
 		reportWriter:                   writer,
+		progress:                       options.Progress,
+		instructionHook:                options.InstructionHook,
+		cacheBackend:                   cacheBackend,
 		isolation:                      options.Isolation,
 		namespaceOptions:               options.NamespaceOptions,
 		configureNetwork:               options.ConfigureNetwork,
@@ -252,6 +274,10 @@ func NewExecutor(logger *logrus.Logger, store storage.Store, options define.Buil
 		maxPullPushRetries:             options.MaxPullPushRetries,
 		retryPullPushDelay:             options.PullPushRetryDelay,
 		ociDecryptConfig:               options.OciDecryptConfig,
+		requirePrimarySource:           options.RequirePrimarySource,
+		maxBaseAge:                     options.MaxBaseAge,
+		baseAgePolicy:                  options.BaseAgePolicy,
+		stageEnv:                       options.StageEnv,
 		terminatedStage:                make(map[string]struct{}),
 		jobs:                           jobs,
 		logRusage:                      options.LogRusage,
@@ -338,6 +364,58 @@ func (b *Executor) resolveNameToImageRef(output string) (types.ImageReference, e
 	return imageRef, err
 }
 
+// checkBaseImageAge enforces b.maxBaseAge, if one was set, against the age of
+// the image that "builder" was created from, using the time it was pulled
+// into local storage as a proxy for how long it's been since it was last
+// refreshed.  Builders which aren't based on an image we have in local
+// storage (for example, "scratch", or another stage) are not checked.
+func (b *Executor) checkBaseImageAge(builder *buildah.Builder, from string) error {
+	if b.maxBaseAge <= 0 || builder.FromImageID == "" {
+		return nil
+	}
+	image, err := b.store.Image(builder.FromImageID)
+	if err != nil {
+		logrus.Debugf("not checking age of base image %q: %v", from, err)
+		return nil
+	}
+	age := time.Since(image.Created)
+	if age <= b.maxBaseAge {
+		return nil
+	}
+	message := fmt.Sprintf("base image %q was pulled %s ago, which is older than the %s limit", from, age.Round(time.Minute), b.maxBaseAge)
+	switch b.baseAgePolicy {
+	case define.BaseAgeFail:
+		return errors.New(message)
+	default:
+		logrus.Warn(message)
+		return nil
+	}
+}
+
+// stageEnvFor returns the environment variables which b.stageEnv scopes to
+// the given stage, keyed by either the stage's name or its numeric position.
+func (b *Executor) stageEnvFor(stage *imagebuilder.Stage) map[string]string {
+	if len(b.stageEnv) == 0 {
+		return nil
+	}
+	if env, ok := b.stageEnv[stage.Name]; ok {
+		return env
+	}
+	return b.stageEnv[fmt.Sprintf("%d", stage.Position)]
+}
+
+// stepIdentifierHashLength is how many characters of the instruction's
+// digest we include in a step identifier, matching the short-ID length used
+// elsewhere in this codebase (e.g. "buildah images"'s truncated IDs).
+const stepIdentifierHashLength = 12
+
+// stepIdentifierHash returns a short, stable hash of a build step's
+// resolved instruction text, for use as the last component of a
+// stage-name/step-index/instruction-hash step identifier.
+func stepIdentifierHash(instruction string) string {
+	return digest.FromString(instruction).Encoded()[:stepIdentifierHashLength]
+}
+
 // waitForStage waits for an entry to be added to terminatedStage indicating
 // that the specified stage has finished.  If there is no stage defined by that
 // name, then it will return (false, nil).  If there is a stage defined by that
@@ -431,6 +509,14 @@ func (b *Executor) buildStage(ctx context.Context, cleanupStages map[int]*StageE
 		return "", nil, err
 	}
 
+	// stageName gives anonymous stages (ones without an "AS" clause) a
+	// human-readable name for use in step identifiers below, since
+	// stage.Name is otherwise just the stage's numeric position as text.
+	stageName := stage.Name
+	if stageName == fmt.Sprintf("%d", stage.Position) {
+		stageName = fmt.Sprintf("stage-%d", stage.Position)
+	}
+
 	b.stagesLock.Lock()
 	stageExecutor := b.startStage(ctx, &stage, stages, output)
 	if stageExecutor.log == nil {
@@ -446,6 +532,12 @@ func (b *Executor) buildStage(ctx context.Context, cleanupStages map[int]*StageE
 				if stepCounter <= len(stage.Node.Children)+1 {
 					prefix += fmt.Sprintf("/%d", len(stage.Node.Children)+1)
 				}
+				// Stable across runs as long as the stage's name (or
+				// position) and the instruction's text don't change, so
+				// that external tooling can diff two builds' logs, or
+				// track a flaky step, by this identifier alone.
+				instruction := fmt.Sprintf(format, args...)
+				prefix += fmt.Sprintf(" %s/%d/%s", stageName, stepCounter, stepIdentifierHash(instruction))
 				prefix += ": "
 			}
 			suffix := "\n"
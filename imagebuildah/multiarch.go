@@ -0,0 +1,61 @@
+package imagebuildah
+
+import (
+	"context"
+
+	"github.com/containers/buildah/define"
+	"github.com/containers/buildah/util"
+	"github.com/containers/common/libimage/manifests"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/storage"
+	v1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// BuildDockerfilesForPlatforms builds the same set of Dockerfiles once per
+// entry in platforms, overriding options.OS and options.Architecture for
+// each build, and then assembles the resulting images into a new manifest
+// list, saving it to local container storage under manifestListName (if
+// manifestListName is not empty) and returning its image ID.  Aside from OS
+// and Architecture, the same options are used for every platform's build.
+//
+// Only the OS and Architecture fields of each platform are used; a
+// platform's Variant, if it has one, is ignored, since Builder has no way to
+// record a variant for the images it produces yet.
+//
+// Builds are run one at a time, in the order that platforms are given,
+// rather than concurrently, since callers are expected to often be sharing
+// a single build context directory across all of the platforms, and the
+// Executor isn't designed to have more than one build reading from the same
+// context directory at once.
+func BuildDockerfilesForPlatforms(ctx context.Context, store storage.Store, options define.BuildOptions, manifestListName string, platforms []v1.Platform, paths ...string) (string, error) {
+	if len(platforms) == 0 {
+		return "", errors.Errorf("error building for multiple platforms: no platforms specified")
+	}
+	list := manifests.Create()
+	for _, platform := range platforms {
+		platformOptions := options
+		platformOptions.OS = platform.OS
+		platformOptions.Architecture = platform.Architecture
+		imageID, _, err := BuildDockerfiles(ctx, store, platformOptions, paths...)
+		if err != nil {
+			return "", errors.Wrapf(err, "error building for platform %s/%s", platform.OS, platform.Architecture)
+		}
+		ref, _, err := util.FindImage(store, "", options.SystemContext, imageID)
+		if err != nil {
+			return "", errors.Wrapf(err, "error locating image %q built for platform %s/%s", imageID, platform.OS, platform.Architecture)
+		}
+		if _, err := list.Add(ctx, options.SystemContext, ref, false); err != nil {
+			return "", errors.Wrapf(err, "error adding image %q built for platform %s/%s to manifest list", imageID, platform.OS, platform.Architecture)
+		}
+	}
+	var names []string
+	if manifestListName != "" {
+		names = []string{manifestListName}
+	}
+	listID, err := list.SaveToImage(store, "", names, manifest.DockerV2ListMediaType)
+	if err != nil {
+		return "", errors.Wrap(err, "error saving manifest list")
+	}
+	return listID, nil
+}
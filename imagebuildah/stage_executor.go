@@ -62,6 +62,9 @@ type StageExecutor struct {
 	output          string
 	containerIDs    []string
 	stage           *imagebuilder.Stage
+	stepIndex       int
+	stepCount       int
+	stepInstruction string
 }
 
 // Preserve informs the stage executor that from this point on, it needs to
@@ -448,6 +451,7 @@ func (s *StageExecutor) Run(run imagebuilder.Run, config docker.Config) error {
 		Terminal:         buildah.WithoutTerminal,
 		Secrets:          s.executor.secrets,
 		RunMounts:        run.Mounts,
+		Context:          s.ctx,
 	}
 	if config.NetworkDisabled {
 		options.ConfigureNetwork = buildah.NetworkDisabled
@@ -540,6 +544,7 @@ func (s *StageExecutor) prepare(ctx context.Context, from string, initializeIBCo
 		BlobDirectory:         s.executor.blobDirectory,
 		SignaturePolicyPath:   s.executor.signaturePolicyPath,
 		ReportWriter:          s.executor.reportWriter,
+		Progress:              s.executor.progress,
 		SystemContext:         s.executor.systemContext,
 		Isolation:             s.executor.isolation,
 		NamespaceOptions:      s.executor.namespaceOptions,
@@ -555,6 +560,7 @@ func (s *StageExecutor) prepare(ctx context.Context, from string, initializeIBCo
 		MaxPullRetries:        s.executor.maxPullPushRetries,
 		PullRetryDelay:        s.executor.retryPullPushDelay,
 		OciDecryptConfig:      s.executor.ociDecryptConfig,
+		RequirePrimarySource:  s.executor.requirePrimarySource,
 	}
 
 	// Check and see if the image is a pseudonym for the end result of a
@@ -569,6 +575,14 @@ func (s *StageExecutor) prepare(ctx context.Context, from string, initializeIBCo
 		return nil, errors.Wrapf(err, "error creating build container")
 	}
 
+	if err := s.executor.checkBaseImageAge(builder, displayFrom); err != nil {
+		return nil, err
+	}
+
+	for k, v := range s.executor.stageEnvFor(stage) {
+		builder.SetEnv(k, v)
+	}
+
 	if initializeIBConfig {
 		volumes := map[string]struct{}{}
 		for _, v := range builder.Volumes() {
@@ -671,6 +685,31 @@ func (s *StageExecutor) getImageRootfs(ctx context.Context, image string) (mount
 	return builder.MountPoint, nil
 }
 
+// instructionHookParams builds the InstructionHookParams describing the
+// instruction that's currently being executed, for passing to the
+// executor's InstructionHook.
+func (s *StageExecutor) instructionHookParams() define.InstructionHookParams {
+	return define.InstructionHookParams{
+		Stage:       s.stage.Name,
+		StepIndex:   s.stepIndex,
+		StepCount:   s.stepCount,
+		Instruction: s.stepInstruction,
+		ContainerID: s.builder.ContainerID,
+	}
+}
+
+// runInstructionHookAfter calls the executor's InstructionHook, if one is
+// set, to report that the instruction described by instructionHookParams()
+// has finished executing.
+func (s *StageExecutor) runInstructionHookAfter() {
+	if s.executor.instructionHook == nil {
+		return
+	}
+	if err := s.executor.instructionHook(false, s.instructionHookParams()); err != nil {
+		logrus.Warnf("instruction hook returned an error after %q: %v", s.stepInstruction, err)
+	}
+}
+
 // Execute runs each of the steps in the stage's parsed tree, in turn.
 func (s *StageExecutor) Execute(ctx context.Context, base string) (imgID string, ref reference.Canonical, err error) {
 	var resourceUsage rusage.Rusage
@@ -749,6 +788,10 @@ func (s *StageExecutor) Execute(ctx context.Context, base string) (imgID string,
 			cacheHitMessage := "--> Using cache"
 			fmt.Fprintf(s.executor.out, "%s %s\n", cacheHitMessage, cacheID)
 		}
+		if s.executor.progress != nil {
+			s.executor.progress.StepFinished(s.stepIndex, s.stepCount, true)
+		}
+		s.runInstructionHookAfter()
 	}
 	logImageID := func(imgID string) {
 		if len(imgID) > 11 {
@@ -757,6 +800,10 @@ func (s *StageExecutor) Execute(ctx context.Context, base string) (imgID string,
 		if s.executor.iidfile == "" {
 			fmt.Fprintf(s.executor.out, "--> %s\n", imgID)
 		}
+		if s.executor.progress != nil {
+			s.executor.progress.StepFinished(s.stepIndex, s.stepCount, false)
+		}
+		s.runInstructionHookAfter()
 	}
 
 	if len(children) == 0 {
@@ -801,6 +848,15 @@ func (s *StageExecutor) Execute(ctx context.Context, base string) (imgID string,
 		if !s.executor.quiet {
 			s.log("%s", step.Original)
 		}
+		s.stepIndex, s.stepCount, s.stepInstruction = i, len(children), step.Original
+		if s.executor.progress != nil {
+			s.executor.progress.StepStarted(i, len(children), step.Original)
+		}
+		if s.executor.instructionHook != nil {
+			if err := s.executor.instructionHook(true, s.instructionHookParams()); err != nil {
+				return "", nil, errors.Wrapf(err, "instruction hook rejected %q", step.Original)
+			}
+		}
 
 		// Check if there's a --from if the step command is COPY.
 		// Also check the chmod and the chown flags for validity.
@@ -925,6 +981,11 @@ func (s *StageExecutor) Execute(ctx context.Context, base string) (imgID string,
 			if err != nil {
 				return "", nil, errors.Wrap(err, "error checking if cached image exists from a previous build")
 			}
+			if cacheID == "" {
+				if cacheID, err = s.lookupCacheBackend(ctx, node, addedContentSummary); err != nil {
+					return "", nil, err
+				}
+			}
 		}
 
 		// If we didn't find a cache entry, or we need to add content
@@ -956,6 +1017,11 @@ func (s *StageExecutor) Execute(ctx context.Context, base string) (imgID string,
 				if err != nil {
 					return "", nil, errors.Wrap(err, "error checking if cached image exists from a previous build")
 				}
+				if cacheID == "" {
+					if cacheID, err = s.lookupCacheBackend(ctx, node, addedContentSummary); err != nil {
+						return "", nil, err
+					}
+				}
 			}
 		} else {
 			// If the instruction would affect our configuration,
@@ -997,6 +1063,9 @@ func (s *StageExecutor) Execute(ctx context.Context, base string) (imgID string,
 			if err != nil {
 				return "", nil, errors.Wrapf(err, "error committing container for step %+v", *step)
 			}
+			if err := s.storeCacheBackend(ctx, node, addedContentSummary, imgID); err != nil {
+				return "", nil, err
+			}
 		}
 		logImageID(imgID)
 
@@ -1195,6 +1264,41 @@ func (s *StageExecutor) tagExistingImage(ctx context.Context, cacheID, output st
 	return img.ID, ref, nil
 }
 
+// cacheBackendKey returns the key that identifies, for the executor's
+// configured CacheBackend, the intermediate image that would result from
+// running currNode against the working container's current base image.
+func (s *StageExecutor) cacheBackendKey(currNode *parser.Node, addedContentDigest string) string {
+	return s.builder.FromImageID + "|" + s.getCreatedBy(currNode, addedContentDigest)
+}
+
+// lookupCacheBackend asks the executor's configured CacheBackend, if one is
+// set, whether it knows of a cached image for the given step.  It returns an
+// empty cacheID, without an error, if no CacheBackend is configured or if
+// the backend doesn't have an answer for us.
+func (s *StageExecutor) lookupCacheBackend(ctx context.Context, currNode *parser.Node, addedContentDigest string) (string, error) {
+	if s.executor.cacheBackend == nil {
+		return "", nil
+	}
+	cacheID, err := s.executor.cacheBackend.Lookup(ctx, s.cacheBackendKey(currNode, addedContentDigest))
+	if err != nil {
+		return "", errors.Wrap(err, "error looking up cached image in cache backend")
+	}
+	return cacheID, nil
+}
+
+// storeCacheBackend records, in the executor's configured CacheBackend, if
+// one is set, that currNode produced the image with the given ID, so that a
+// later lookupCacheBackend call for the same step can find it again.
+func (s *StageExecutor) storeCacheBackend(ctx context.Context, currNode *parser.Node, addedContentDigest, imageID string) error {
+	if s.executor.cacheBackend == nil {
+		return nil
+	}
+	if err := s.executor.cacheBackend.Store(ctx, s.cacheBackendKey(currNode, addedContentDigest), imageID); err != nil {
+		return errors.Wrap(err, "error recording cached image in cache backend")
+	}
+	return nil
+}
+
 // intermediateImageExists returns true if an intermediate image of currNode exists in the image store from a previous build.
 // It verifies this by checking the parent of the top layer of the image and the history.
 func (s *StageExecutor) intermediateImageExists(ctx context.Context, currNode *parser.Node, addedContentDigest string, buildAddsLayer bool) (string, error) {
@@ -1356,6 +1460,7 @@ func (s *StageExecutor) commit(ctx context.Context, createdBy string, emptyLayer
 		Compression:           s.executor.compression,
 		SignaturePolicyPath:   s.executor.signaturePolicyPath,
 		ReportWriter:          writer,
+		Progress:              s.executor.progress,
 		PreferredManifestType: s.executor.outputFormat,
 		SystemContext:         s.executor.systemContext,
 		Squash:                s.executor.squash,
@@ -6,13 +6,16 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/containers/buildah/util"
+	"github.com/containers/common/pkg/apparmor"
 	"github.com/containers/storage"
+	"github.com/containers/storage/drivers/overlay"
 	"github.com/containers/storage/pkg/system"
 	"github.com/containers/storage/pkg/unshare"
 	"github.com/pkg/errors"
@@ -85,6 +88,12 @@ func hostInfo() map[string]interface{} {
 	}
 	info["kernel"] = kv
 
+	info["idmappedMounts"] = kernelSupportsIDMappedMounts(kv)
+	info["apparmorEnabled"] = apparmor.IsEnabled()
+	info["seccompEnabled"] = SeccompEnabled()
+	info["isolationBackends"] = isolationBackends()
+	info["binfmtHandlers"] = binfmtHandlers()
+
 	up, err := readUptime()
 	if err != nil {
 		logrus.Error(err, "error reading up time")
@@ -147,6 +156,13 @@ func storeInfo(store storage.Store) (map[string]interface{}, error) {
 		status[pair[0]] = pair[1]
 	}
 	info["GraphStatus"] = status
+	if store.GraphDriverName() == "overlay" {
+		supportsNative, err := overlay.SupportsNativeOverlay(store.GraphRoot(), store.RunRoot())
+		if err != nil {
+			logrus.Error(err, "error checking native overlay support")
+		}
+		info["NativeOverlayDiff"] = supportsNative
+	}
 	images, err := store.Images()
 	if err != nil {
 		logrus.Error(err, "error getting number of images")
@@ -190,6 +206,61 @@ func readUptime() (string, error) {
 	return string(f[0]), nil
 }
 
+// isolationBackends returns the names of the process isolation backends
+// that buildah's "run"/"bud" commands know how to use on this OS.
+func isolationBackends() []string {
+	if runtime.GOOS != "linux" {
+		return nil
+	}
+	return []string{"oci", "chroot", "rootless"}
+}
+
+// kernelVersionRegexp matches the leading "<major>.<minor>" of a kernel
+// release string like "5.15.0-91-generic".
+var kernelVersionRegexp = regexp.MustCompile(`^(\d+)\.(\d+)`)
+
+// kernelSupportsIDMappedMounts makes a best-effort guess, based on the
+// kernel version string reported in /proc/version, as to whether the
+// running kernel is new enough to support idmapped mounts (which landed in
+// 5.12, with filesystem-specific support following in later releases). It
+// does not attempt to exercise mount_setattr(2), since that syscall isn't
+// available through our vendored copy of golang.org/x/sys/unix.
+func kernelSupportsIDMappedMounts(kernelVersion string) bool {
+	m := kernelVersionRegexp.FindStringSubmatch(kernelVersion)
+	if m == nil {
+		return false
+	}
+	major, err := strconv.Atoi(m[1])
+	if err != nil {
+		return false
+	}
+	minor, err := strconv.Atoi(m[2])
+	if err != nil {
+		return false
+	}
+	return major > 5 || (major == 5 && minor >= 12)
+}
+
+// binfmtHandlers lists the names of the binfmt_misc handlers registered
+// with the kernel, which is how cross-architecture emulation (e.g. via
+// qemu-user-static) is typically wired up for use during builds.
+func binfmtHandlers() []string {
+	const binfmtDir = "/proc/sys/fs/binfmt_misc"
+	entries, err := ioutil.ReadDir(binfmtDir)
+	if err != nil {
+		return nil
+	}
+	var handlers []string
+	for _, entry := range entries {
+		switch entry.Name() {
+		case "register", "status":
+			continue
+		}
+		handlers = append(handlers, entry.Name())
+	}
+	return handlers
+}
+
 // getHostDistributionInfo returns a map containing the host's distribution and version
 func getHostDistributionInfo() map[string]string {
 	dist := make(map[string]string)
@@ -0,0 +1,85 @@
+package buildah
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containers/storage"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+// lockFile is the name of the file, kept alongside a container's builder
+// state file, that BuilderLock uses to synchronize access to that state
+// between processes.
+const lockFile = Package + ".lock"
+
+// BuilderLock is an advisory, per-container lock that callers can use to
+// keep two processes from reading and writing a working container's
+// persisted builder state (and its mounts) at the same time.  It's built on
+// the kernel's flock(2), so if the process holding the lock dies or is
+// killed, the lock is released automatically; there's no separate notion of
+// a "stale" lock to detect or recover from.
+type BuilderLock struct {
+	file *os.File
+}
+
+// LockBuilder returns a BuilderLock for the container with the given ID in
+// store.  Acquiring it does not require that a Builder for the container
+// already be open, so it can be used to guard the OpenBuilder/Save sequence
+// itself, and not just an already-open Builder's later calls to Save.
+func LockBuilder(store storage.Store, containerID string) (*BuilderLock, error) {
+	cdir, err := store.ContainerDirectory(containerID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error locating state directory for container %q", containerID)
+	}
+	file, err := os.OpenFile(filepath.Join(cdir, lockFile), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening lock file for container %q", containerID)
+	}
+	return &BuilderLock{file: file}, nil
+}
+
+// TryLock makes one non-blocking attempt to acquire the lock, returning
+// false instead of waiting if it's currently held by another owner.
+func (l *BuilderLock) TryLock() (bool, error) {
+	if err := unix.Flock(int(l.file.Fd()), unix.LOCK_EX|unix.LOCK_NB); err != nil {
+		if err == unix.EWOULDBLOCK {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "error acquiring container lock")
+	}
+	return true, nil
+}
+
+// Lock acquires the lock, polling until it succeeds or ctx is canceled,
+// whichever happens first.
+func (l *BuilderLock) Lock(ctx context.Context) error {
+	for {
+		ok, err := l.TryLock()
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+// Unlock releases the lock.
+func (l *BuilderLock) Unlock() error {
+	return unix.Flock(int(l.file.Fd()), unix.LOCK_UN)
+}
+
+// Close releases the resources associated with the lock.  It should not be
+// used again afterward; acquire a new one with LockBuilder if needed.
+func (l *BuilderLock) Close() error {
+	return l.file.Close()
+}
@@ -62,6 +62,38 @@ func imageNamePrefix(imageName string) string {
 	return prefix
 }
 
+// imageRegistryHostname returns the registry hostname portion of a
+// fully-qualified image reference, or an empty string if the reference
+// doesn't appear to name a specific registry (e.g. it's a short name that
+// still needs to be resolved against search registries).
+func imageRegistryHostname(imageName string) string {
+	imageName = strings.TrimPrefix(imageName, "docker://")
+	parts := strings.SplitN(imageName, "/", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	host := parts[0]
+	if host != "localhost" && !strings.ContainsAny(host, ".:") {
+		return ""
+	}
+	return host
+}
+
+// checkPrimarySource compares the registry that a base image was requested
+// from against the registry which actually served it, returning an error if
+// requirePrimarySource is set and the two differ.
+func checkPrimarySource(requested, pulledFrom string, requirePrimarySource bool) error {
+	if !requirePrimarySource || pulledFrom == "" {
+		return nil
+	}
+	wantHost := imageRegistryHostname(requested)
+	gotHost := imageRegistryHostname(pulledFrom)
+	if wantHost == "" || gotHost == "" || wantHost == gotHost {
+		return nil
+	}
+	return errors.Errorf("base image %q was pulled from %q instead of the requested registry %q; refusing due to --require-primary-source", requested, pulledFrom, wantHost)
+}
+
 func newContainerIDMappingOptions(idmapOptions *define.IDMappingOptions) storage.IDMappingOptions {
 	var options storage.IDMappingOptions
 	if idmapOptions != nil {
@@ -75,6 +107,12 @@ func newContainerIDMappingOptions(idmapOptions *define.IDMappingOptions) storage
 			options.HostUIDMapping = true
 			options.HostGIDMapping = true
 		}
+		if idmapOptions.AutoUserNs {
+			options.AutoUserNs = true
+			options.HostUIDMapping = false
+			options.HostGIDMapping = false
+			options.AutoUserNsOpts.Size = idmapOptions.AutoUserNsSize
+		}
 	}
 	return options
 }
@@ -102,9 +140,10 @@ func findUnusedContainer(name string, containers []storage.Container) string {
 
 func newBuilder(ctx context.Context, store storage.Store, options BuilderOptions) (*Builder, error) {
 	var (
-		ref types.ImageReference
-		img *storage.Image
-		err error
+		ref        types.ImageReference
+		img        *storage.Image
+		err        error
+		pulledFrom string
 	)
 
 	if options.FromImage == BaseImageFakeName {
@@ -133,6 +172,10 @@ func newBuilder(ctx context.Context, store storage.Store, options BuilderOptions
 		pullOptions.SignaturePolicyPath = options.SignaturePolicyPath
 		pullOptions.Writer = options.ReportWriter
 
+		progressChan, stopProgress := startBlobProgress(options.Progress)
+		defer stopProgress()
+		pullOptions.Progress = progressChan
+
 		maxRetries := uint(options.MaxPullRetries)
 		pullOptions.MaxRetries = &maxRetries
 
@@ -150,6 +193,12 @@ func newBuilder(ctx context.Context, store storage.Store, options BuilderOptions
 			if err != nil {
 				return nil, err
 			}
+			if names := pulledImages[0].Names(); len(names) > 0 {
+				pulledFrom = names[0]
+			}
+		}
+		if err := checkPrimarySource(options.FromImage, pulledFrom, options.RequirePrimarySource); err != nil {
+			return nil, err
 		}
 	}
 
@@ -281,6 +330,10 @@ func newBuilder(ctx context.Context, store storage.Store, options BuilderOptions
 		Devices:         options.Devices,
 	}
 
+	if pulledFrom != "" {
+		builder.ImageAnnotations[BuilderBaseImageSourceAnnotation] = pulledFrom
+	}
+
 	if options.Mount {
 		_, err = builder.Mount(container.MountLabel())
 		if err != nil {
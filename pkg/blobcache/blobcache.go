@@ -227,6 +227,10 @@ func (s *blobCacheSource) GetManifest(ctx context.Context, instanceDigest *diges
 		filename := filepath.Join(s.reference.directory, makeFilename(*instanceDigest, false))
 		manifestBytes, err := ioutil.ReadFile(filename)
 		if err == nil {
+			if digest.FromBytes(manifestBytes) != *instanceDigest {
+				s.cacheErrors++
+				return nil, "", errors.Errorf("cached manifest %q does not match expected digest %q", filename, *instanceDigest)
+			}
 			s.cacheHits++
 			return manifestBytes, manifest.GuessMIMEType(manifestBytes), nil
 		}
@@ -243,6 +247,31 @@ func (s *blobCacheSource) HasThreadSafeGetBlob() bool {
 	return s.source.HasThreadSafeGetBlob()
 }
 
+// verifyingReadCloser wraps a ReadCloser backed by a locally-cached blob and
+// confirms, as the blob is read, that its contents hash to the digest we
+// expect it to have.  This keeps a corrupted or tampered-with cache entry
+// from being trusted just because its filename claims to match a digest.
+type verifyingReadCloser struct {
+	io.ReadCloser
+	verifier digest.Verifier
+	expected digest.Digest
+}
+
+func newVerifyingReadCloser(rc io.ReadCloser, expected digest.Digest) io.ReadCloser {
+	return &verifyingReadCloser{ReadCloser: rc, verifier: expected.Verifier(), expected: expected}
+}
+
+func (v *verifyingReadCloser) Read(p []byte) (int, error) {
+	n, err := v.ReadCloser.Read(p)
+	if n > 0 {
+		_, _ = v.verifier.Write(p[:n])
+	}
+	if err == io.EOF && !v.verifier.Verified() {
+		return n, errors.Errorf("cached blob does not match expected digest %q", v.expected)
+	}
+	return n, err
+}
+
 func (s *blobCacheSource) GetBlob(ctx context.Context, blobinfo types.BlobInfo, cache types.BlobInfoCache) (io.ReadCloser, int64, error) {
 	present, size, err := s.reference.HasBlob(blobinfo)
 	if err != nil {
@@ -256,7 +285,7 @@ func (s *blobCacheSource) GetBlob(ctx context.Context, blobinfo types.BlobInfo,
 				s.mu.Lock()
 				s.cacheHits++
 				s.mu.Unlock()
-				return f, size, nil
+				return newVerifyingReadCloser(f, blobinfo.Digest), size, nil
 			}
 			if !os.IsNotExist(err) {
 				s.mu.Lock()
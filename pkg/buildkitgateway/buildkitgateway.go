@@ -0,0 +1,35 @@
+// Package buildkitgateway is the extension point for speaking enough of
+// BuildKit's gateway/control gRPC protocol that tools which normally talk to
+// buildkitd (for example "docker buildx --builder remote") could submit
+// builds to a buildah-backed builder instead.
+//
+// That protocol is defined by protobuf messages and gRPC service
+// definitions in github.com/moby/buildkit (api/services/control,
+// frontend/gateway/pb, and the LLB op definitions they depend on), none of
+// which are vendored into this tree.  Hand-writing compatible .pb.go stubs
+// here, without the real generated code to check them against, would risk
+// producing something that merely looks like the protocol without actually
+// interoperating with real BuildKit clients, which would be worse than not
+// implementing it.  Serve returns an explanatory error instead of a
+// dysfunctional server so that callers get an honest failure rather than a
+// stalled connection.
+package buildkitgateway
+
+import (
+	"context"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNotVendored is returned by Serve because github.com/moby/buildkit's
+// gateway and control protobuf definitions aren't vendored into this
+// module, so there's no compatible protocol to speak on the listener.
+var ErrNotVendored = errors.New("BuildKit gateway/control gRPC protocol support requires github.com/moby/buildkit's protobuf definitions, which aren't vendored into this build of buildah")
+
+// Serve would run a BuildKit gateway/control-compatible gRPC server on
+// listener, translating LLB solve requests into imagebuildah executions.
+// In this build, it always fails with ErrNotVendored.
+func Serve(ctx context.Context, listener net.Listener) error {
+	return ErrNotVendored
+}
@@ -48,77 +48,87 @@ type NameSpaceResults struct {
 
 // BudResults represents the results for Bud flags
 type BudResults struct {
-	Annotation          []string
-	Authfile            string
-	BuildArg            []string
-	CacheFrom           string
-	CertDir             string
-	Compress            bool
-	Creds               string
-	DisableCompression  bool
-	DisableContentTrust bool
-	IgnoreFile          string
-	File                []string
-	Format              string
-	From                string
-	Iidfile             string
-	Label               []string
-	Logfile             string
-	Manifest            string
-	NoCache             bool
-	Timestamp           int64
-	Pull                bool
-	PullAlways          bool
-	PullNever           bool
-	Quiet               bool
-	Rm                  bool
-	Runtime             string
-	RuntimeFlags        []string
-	Secrets             []string
-	SignaturePolicy     string
-	SignBy              string
-	Squash              bool
-	Stdin               bool
-	Tag                 []string
-	Target              string
-	TLSVerify           bool
-	Jobs                int
-	LogRusage           bool
-	RusageLogFile       string
+	Annotation           []string
+	Authfile             string
+	BuildArg             []string
+	CacheBackend         string
+	CacheFrom            string
+	CertDir              string
+	Compress             bool
+	Creds                string
+	DisableCompression   bool
+	DisableContentTrust  bool
+	IgnoreFile           string
+	File                 []string
+	ContainerfileContent string
+	Format               string
+	From                 string
+	Iidfile              string
+	Label                []string
+	Logfile              string
+	Manifest             string
+	NoCache              bool
+	Timestamp            int64
+	Pull                 bool
+	PullAlways           bool
+	PullNever            bool
+	Quiet                bool
+	Rm                   bool
+	Runtime              string
+	RuntimeFlags         []string
+	Secrets              []string
+	SignaturePolicy      string
+	SignBy               string
+	Squash               bool
+	Stdin                bool
+	Tag                  []string
+	Target               string
+	TLSVerify            bool
+	Jobs                 int
+	LogRusage            bool
+	RusageLogFile        string
+	StorageGCThreshold   uint
+	StageEnv             []string
+	Scan                 string
+	ScanFailOn           string
+	Watch                bool
 }
 
 // FromAndBugResults represents the results for common flags
 // in bud and from
 type FromAndBudResults struct {
-	AddHost        []string
-	BlobCache      string
-	CapAdd         []string
-	CapDrop        []string
-	CgroupParent   string
-	CPUPeriod      uint64
-	CPUQuota       int64
-	CPUSetCPUs     string
-	CPUSetMems     string
-	CPUShares      uint64
-	DecryptionKeys []string
-	Devices        []string
-	DNSSearch      []string
-	DNSServers     []string
-	DNSOptions     []string
-	HTTPProxy      bool
-	Isolation      string
-	Memory         string
-	MemorySwap     string
-	SecurityOpt    []string
-	ShmSize        string
-	Ulimit         []string
-	Volumes        []string
+	AddHost              []string
+	BlobCache            string
+	CapAdd               []string
+	CapDrop              []string
+	CgroupParent         string
+	CPUPeriod            uint64
+	CPUQuota             int64
+	CPUSetCPUs           string
+	CPUSetMems           string
+	CPUShares            uint64
+	DecryptionKeys       []string
+	Devices              []string
+	DNSSearch            []string
+	DNSServers           []string
+	DNSOptions           []string
+	HTTPProxy            bool
+	Isolation            string
+	Memory               string
+	MemorySwap           string
+	RequirePrimarySource bool
+	MaxBaseAge           string
+	BaseAgePolicy        string
+	SecurityOpt          []string
+	ShmSize              string
+	Ulimit               []string
+	Volumes              []string
 }
 
 // GetUserNSFlags returns the common flags for usernamespace
 func GetUserNSFlags(flags *UserNSResults) pflag.FlagSet {
 	usernsFlags := pflag.FlagSet{}
-	usernsFlags.StringVar(&flags.UserNS, "userns", "", "'container', `path` of user namespace to join, or 'host'")
+	usernsFlags.StringVar(&flags.UserNS, "userns", "", "'container', `path` of user namespace to join, 'host', or 'auto[:size=N]' to have a range allocated automatically")
 	usernsFlags.StringSliceVar(&flags.UserNSUIDMap, "userns-uid-map", []string{}, "`containerUID:hostUID:length` UID mapping to use in user namespace")
 	usernsFlags.StringSliceVar(&flags.UserNSGIDMap, "userns-gid-map", []string{}, "`containerGID:hostGID:length` GID mapping to use in user namespace")
 	usernsFlags.StringVar(&flags.UserNSUIDMapUser, "userns-uid-map-user", "", "`name` of entries from /etc/subuid to use to set user namespace UID mapping")
@@ -171,6 +181,26 @@ func GetLayerFlags(flags *LayerResults) pflag.FlagSet {
 
 // Note: GetLayerFlagsCompletion is not needed since GetLayerFlags only contains bool flags
 
+// ValidatePullFlags returns an error if a command line set more than one of
+// the mutually exclusive --pull, --pull-always, and --pull-never flags that
+// GetBudFlags and GetFromAndBudFlags both add to fs, so that commands built
+// around either flag set don't each need to reimplement this check.  Since a
+// bool flag's zero value can't be told apart from an explicit "false", this
+// has to consult fs's record of which flags were changed, rather than the
+// values collected in a BudResults or FromAndBudResults.
+func ValidatePullFlags(fs *pflag.FlagSet) error {
+	pullFlagsCount := 0
+	for _, name := range []string{"pull", "pull-always", "pull-never"} {
+		if fs.Changed(name) {
+			pullFlagsCount++
+		}
+	}
+	if pullFlagsCount > 1 {
+		return errors.Errorf("can only set one of 'pull' or 'pull-always' or 'pull-never'")
+	}
+	return nil
+}
+
 // GetBudFlags returns common bud flags
 func GetBudFlags(flags *BudResults) pflag.FlagSet {
 	fs := pflag.FlagSet{}
@@ -178,6 +208,7 @@ func GetBudFlags(flags *BudResults) pflag.FlagSet {
 	fs.StringArrayVar(&flags.Annotation, "annotation", []string{}, "Set metadata for an image (default [])")
 	fs.StringVar(&flags.Authfile, "authfile", "", "path of the authentication file.")
 	fs.StringArrayVar(&flags.BuildArg, "build-arg", []string{}, "`argument=value` to supply to the builder")
+	fs.StringVar(&flags.CacheBackend, "cache-backend", "", "name of a registered cache backend to also consult and record intermediate images in, alongside local container storage (\"local\" selects local container storage explicitly; unset means use only local container storage)")
 	fs.StringVar(&flags.CacheFrom, "cache-from", "", "Images to utilise as potential cache sources. The build process does not currently support caching so this is a NOOP.")
 	fs.StringVar(&flags.CertDir, "cert-dir", "", "use certificates at the specified path to access the registry")
 	fs.BoolVar(&flags.Compress, "compress", false, "This is legacy option, which has no effect on the image")
@@ -187,6 +218,7 @@ func GetBudFlags(flags *BudResults) pflag.FlagSet {
 	fs.StringVar(&flags.From, "from", "", "image name used to replace the value in the first FROM instruction in the Containerfile")
 	fs.StringVar(&flags.IgnoreFile, "ignorefile", "", "path to an alternate .dockerignore file")
 	fs.StringSliceVarP(&flags.File, "file", "f", []string{}, "`pathname or URL` of a Dockerfile")
+	fs.StringVar(&flags.ContainerfileContent, "containerfile-content", "", "use the given `content` as the Containerfile instead of reading one from --file")
 	fs.StringVar(&flags.Format, "format", DefaultFormat(), "`format` of the built image's manifest and metadata. Use BUILDAH_FORMAT environment variable to override.")
 	fs.StringVar(&flags.Iidfile, "iidfile", "", "`file` to write the image ID to")
 	fs.IntVar(&flags.Jobs, "jobs", 1, "how many stages to run in parallel")
@@ -222,6 +254,11 @@ func GetBudFlags(flags *BudResults) pflag.FlagSet {
 		panic(fmt.Sprintf("error marking the signature-policy flag as hidden: %v", err))
 	}
 	fs.BoolVar(&flags.Squash, "squash", false, "squash newly built layers into a single new layer")
+	fs.UintVar(&flags.StorageGCThreshold, "storage-gc-threshold", 0, "if storage usage is at or above this `percentage` (1-100) when the build starts, prune least-recently-created dangling images to make room; 0 disables the check")
+	fs.StringArrayVar(&flags.StageEnv, "stage-env", []string{}, "set an environment variable for the RUN steps of one stage only, in `stage:name=value` format")
+	fs.StringVar(&flags.Scan, "scan", "", "scan the built image for vulnerabilities using the given scanner `command` and record the results as an artifact")
+	fs.StringVar(&flags.ScanFailOn, "scan-fail-on", "", "with --scan, fail the build if a vulnerability at or above this `severity` is found")
+	fs.BoolVar(&flags.Watch, "watch", false, "watch the build context for changes and rebuild automatically")
 	fs.BoolVar(&flags.Stdin, "stdin", false, "pass stdin into containers")
 	fs.StringArrayVarP(&flags.Tag, "tag", "t", []string{}, "tagged `name` to apply to the built image")
 	fs.StringVar(&flags.Target, "target", "", "set the target build stage to build")
@@ -238,8 +275,10 @@ func GetBudFlagsCompletions() commonComp.FlagCompletions {
 	flagCompletion["annotation"] = commonComp.AutocompleteNone
 	flagCompletion["authfile"] = commonComp.AutocompleteDefault
 	flagCompletion["build-arg"] = commonComp.AutocompleteNone
+	flagCompletion["cache-backend"] = commonComp.AutocompleteNone
 	flagCompletion["cache-from"] = commonComp.AutocompleteNone
 	flagCompletion["cert-dir"] = commonComp.AutocompleteDefault
+	flagCompletion["containerfile-content"] = commonComp.AutocompleteNone
 	flagCompletion["creds"] = commonComp.AutocompleteNone
 	flagCompletion["file"] = commonComp.AutocompleteDefault
 	flagCompletion["from"] = commonComp.AutocompleteDefault
@@ -256,6 +295,9 @@ func GetBudFlagsCompletions() commonComp.FlagCompletions {
 	flagCompletion["secret"] = commonComp.AutocompleteNone
 	flagCompletion["sign-by"] = commonComp.AutocompleteNone
 	flagCompletion["signature-policy"] = commonComp.AutocompleteNone
+	flagCompletion["scan"] = commonComp.AutocompleteDefault
+	flagCompletion["scan-fail-on"] = commonComp.AutocompleteNone
+	flagCompletion["storage-gc-threshold"] = commonComp.AutocompleteNone
 	flagCompletion["tag"] = commonComp.AutocompleteNone
 	flagCompletion["target"] = commonComp.AutocompleteNone
 	flagCompletion["timestamp"] = commonComp.AutocompleteNone
@@ -291,8 +333,13 @@ func GetFromAndBudFlags(flags *FromAndBudResults, usernsResults *UserNSResults,
 	fs.StringSliceVar(&flags.DNSOptions, "dns-option", defaultContainerConfig.Containers.DNSOptions, "Set custom DNS options")
 	fs.BoolVar(&flags.HTTPProxy, "http-proxy", true, "pass through HTTP Proxy environment variables")
 	fs.StringVar(&flags.Isolation, "isolation", DefaultIsolation(), "`type` of process isolation to use. Use BUILDAH_ISOLATION environment variable to override.")
+	fs.Bool("read-only", false, "mount the container's root filesystem read-only for every RUN step, with tmpfs mounts over /tmp, /run, and /var/tmp")
+	fs.Bool("host-containers-internal", true, "add a \"host.containers.internal\" entry to /etc/hosts for every RUN step, resolving to the rootless networking gateway address")
 	fs.StringVarP(&flags.Memory, "memory", "m", "", "memory limit (format: <number>[<unit>], where unit = b, k, m or g)")
 	fs.StringVar(&flags.MemorySwap, "memory-swap", "", "swap limit equal to memory plus swap: '-1' to enable unlimited swap")
+	fs.BoolVar(&flags.RequirePrimarySource, "require-primary-source", false, "fail instead of silently falling back to a mirror or alternate search registry for the base image")
+	fs.StringVar(&flags.MaxBaseAge, "max-base-age", "", "flag a FROM image as stale if it was pulled more than `duration` ago (e.g. \"720h\")")
+	fs.StringVar(&flags.BaseAgePolicy, "base-age-policy", "warn", "what to do when a FROM image is older than --max-base-age: \"warn\" or \"fail\"")
 	fs.String("arch", runtime.GOARCH, "set the ARCH of the image to the provided value instead of the architecture of the host")
 	fs.String("os", runtime.GOOS, "prefer `OS` instead of the running OS when pulling images")
 	fs.String("variant", "", "override the `variant` of the specified image")
@@ -333,6 +380,9 @@ func GetFromAndBudFlagsCompletions() commonComp.FlagCompletions {
 	flagCompletion["memory"] = commonComp.AutocompleteNone
 	flagCompletion["memory-swap"] = commonComp.AutocompleteNone
 	flagCompletion["os"] = commonComp.AutocompleteNone
+	flagCompletion["read-only"] = commonComp.AutocompleteNone
+	flagCompletion["host-containers-internal"] = commonComp.AutocompleteNone
+	flagCompletion["require-primary-source"] = commonComp.AutocompleteNone
 	flagCompletion["security-opt"] = commonComp.AutocompleteNone
 	flagCompletion["shm-size"] = commonComp.AutocompleteNone
 	flagCompletion["ulimit"] = commonComp.AutocompleteNone
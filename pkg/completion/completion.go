@@ -21,3 +21,28 @@ func AutocompleteNamespaceFlag(cmd *cobra.Command, args []string, toComplete str
 	}
 	return completions, cobra.ShellCompDirectiveDefault
 }
+
+// AutocompleteIsolation - Autocomplete the isolation flag.
+// -> oci, chroot, rootless
+func AutocompleteIsolation(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for _, comp := range []string{"oci", "chroot", "rootless"} {
+		if strings.HasPrefix(comp, toComplete) {
+			completions = append(completions, comp)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// AutocompleteImageFormat - Autocomplete the format flag for commands that
+// write local images, i.e. "oci" or "docker".
+// -> oci, docker
+func AutocompleteImageFormat(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	var completions []string
+	for _, comp := range []string{"oci", "docker"} {
+		if strings.HasPrefix(comp, toComplete) {
+			completions = append(completions, comp)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
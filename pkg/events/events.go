@@ -0,0 +1,142 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/containers/storage"
+)
+
+// Type identifies the kind of operation an Event records.
+type Type string
+
+const (
+	// From records a "buildah from".
+	From Type = "from"
+	// Run records a "buildah run".
+	Run Type = "run"
+	// Copy records a "buildah copy" or "buildah add".
+	Copy Type = "copy"
+	// Commit records a "buildah commit".
+	Commit Type = "commit"
+	// Push records a "buildah push".
+	Push Type = "push"
+	// Remove records a "buildah rm" or "buildah rmi".
+	Remove Type = "rm"
+
+	logFileName = "buildah-events.log"
+)
+
+// Event is a single record of a significant, potentially security-relevant
+// operation: who ran it, when, against what container or image, and with
+// what arguments.  Write appends Events to the log in a store's graph root,
+// and Read (optionally filtered) reads them back, for use by "buildah
+// events" and by anything else that needs an audit trail of buildah's
+// operations.
+type Event struct {
+	Time time.Time `json:"time"`
+	Type Type      `json:"type"`
+	ID   string    `json:"id,omitempty"`
+	Name string    `json:"name,omitempty"`
+	User string    `json:"user,omitempty"`
+	Args []string  `json:"args,omitempty"`
+}
+
+// logFile returns the path to the event log file in store's graph root.
+func logFile(store storage.Store) string {
+	return filepath.Join(store.GraphRoot(), logFileName)
+}
+
+// writeMutex serializes appends to the event log from this process; it
+// doesn't protect against other processes writing to the same store's log
+// concurrently, since the log file itself isn't locked.
+var writeMutex sync.Mutex
+
+// Write appends event to the event log in store's graph root, filling in
+// Time and User if they weren't already set.
+func Write(store storage.Store, event Event) error {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	if event.User == "" {
+		if u, err := user.Current(); err == nil {
+			event.User = u.Username
+		}
+	}
+
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	writeMutex.Lock()
+	defer writeMutex.Unlock()
+
+	f, err := os.OpenFile(logFile(store), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(encoded, '\n'))
+	return err
+}
+
+// Filter is a predicate used by Read to decide whether to include an event
+// in its results.
+type Filter func(Event) bool
+
+// Since returns a Filter that matches events recorded at or after t.
+func Since(t time.Time) Filter {
+	return func(e Event) bool { return !e.Time.Before(t) }
+}
+
+// Until returns a Filter that matches events recorded at or before t.
+func Until(t time.Time) Filter {
+	return func(e Event) bool { return !e.Time.After(t) }
+}
+
+// TypeIs returns a Filter that matches events of the given type.
+func TypeIs(typ Type) Filter {
+	return func(e Event) bool { return e.Type == typ }
+}
+
+// Read reads back the events recorded in store's event log which match
+// every one of filters, in the order they were originally recorded.  A
+// store with no recorded events yet is not an error; Read just returns no
+// events.
+func Read(store storage.Store, filters ...Filter) ([]Event, error) {
+	f, err := os.Open(logFile(store))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		matched := true
+		for _, filter := range filters {
+			if !filter(event) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			events = append(events, event)
+		}
+	}
+	return events, scanner.Err()
+}
@@ -2,10 +2,12 @@ package formats
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"reflect"
 	"strings"
 	"text/tabwriter"
 	"text/template"
@@ -41,6 +43,54 @@ type StdoutTemplateArray struct {
 	Fields   map[string]string
 }
 
+// DSVStructArray writes Output as delimiter-separated values, one record per
+// element, keeping only the named Columns (matched against each element's
+// exported field names, in the order given) and translating each column
+// name into a friendlier header via Headers, if a translation is present
+// there, when writing the header record.
+type DSVStructArray struct {
+	Output    []interface{}
+	Columns   []string
+	Headers   map[string]string
+	Delimiter rune
+}
+
+// Out method for delimiter-separated values, such as CSV (the default, if
+// Delimiter is left as its zero value) or TSV (Delimiter: '\t')
+func (d DSVStructArray) Out() error {
+	w := csv.NewWriter(os.Stdout)
+	if d.Delimiter != 0 {
+		w.Comma = d.Delimiter
+	}
+	header := make([]string, len(d.Columns))
+	for i, column := range d.Columns {
+		if name, ok := d.Headers[column]; ok {
+			header[i] = name
+		} else {
+			header[i] = column
+		}
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for _, raw := range d.Output {
+		v := reflect.ValueOf(raw)
+		record := make([]string, len(d.Columns))
+		for i, column := range d.Columns {
+			field := v.FieldByName(column)
+			if !field.IsValid() {
+				return errors.Errorf("no such column %q", column)
+			}
+			record[i] = fmt.Sprintf("%v", field.Interface())
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
 // JSONStruct for JSON output
 type JSONStruct struct {
 	Output interface{}
@@ -132,7 +182,7 @@ func (j JSONStruct) Out() error {
 	return nil
 }
 
-//Out method for Go templates
+// Out method for Go templates
 func (t StdoutTemplate) Out() error {
 	tmpl, err := template.New("image").Parse(t.Template)
 	if err != nil {
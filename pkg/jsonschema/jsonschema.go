@@ -0,0 +1,95 @@
+// Package jsonschema generates minimal JSON Schema (draft-07) documents
+// from the Go structs that buildah's `--format json` output uses, so that
+// downstream tooling can code-generate types against them or detect
+// breaking changes between releases, instead of depending on buildah's
+// output shape by inspection.
+package jsonschema
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SchemaVersion is the JSON Schema draft that documents produced by this
+// package declare themselves to conform to.
+const SchemaVersion = "http://json-schema.org/draft-07/schema#"
+
+// Document is a (deliberately small) subset of JSON Schema: enough to
+// describe the flat, JSON-tagged structs that buildah uses for its `--json`
+// and `--format json` output.
+type Document struct {
+	Schema     string               `json:"$schema"`
+	ID         string               `json:"$id,omitempty"`
+	Title      string               `json:"title,omitempty"`
+	Type       string               `json:"type"`
+	Items      *Document            `json:"items,omitempty"`
+	Properties map[string]*Document `json:"properties,omitempty"`
+}
+
+// ForType generates a Document describing the JSON representation of v,
+// which must be a struct, a pointer to one, or a slice of either. id is
+// recorded as the schema's "$id" so that consumers can distinguish one
+// buildah output shape from another.
+func ForType(v interface{}, id string) (*Document, error) {
+	t := reflect.TypeOf(v)
+	doc, err := forGoType(t)
+	if err != nil {
+		return nil, err
+	}
+	doc.Schema = SchemaVersion
+	doc.ID = id
+	return doc, nil
+}
+
+func forGoType(t reflect.Type) (*Document, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		items, err := forGoType(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Document{Type: "array", Items: items}, nil
+	case reflect.Struct:
+		props := make(map[string]*Document, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" { // unexported
+				continue
+			}
+			name := field.Name
+			if tag, ok := field.Tag.Lookup("json"); ok {
+				parts := strings.Split(tag, ",")
+				if parts[0] == "-" {
+					continue
+				}
+				if parts[0] != "" {
+					name = parts[0]
+				}
+			}
+			propDoc, err := forGoType(field.Type)
+			if err != nil {
+				return nil, errors.Wrapf(err, "field %q", field.Name)
+			}
+			props[name] = propDoc
+		}
+		return &Document{Type: "object", Properties: props}, nil
+	case reflect.String:
+		return &Document{Type: "string"}, nil
+	case reflect.Bool:
+		return &Document{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Document{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Document{Type: "number"}, nil
+	case reflect.Map, reflect.Interface:
+		return &Document{Type: "object"}, nil
+	default:
+		return nil, errors.Errorf("unsupported type %s for JSON schema generation", t)
+	}
+}
@@ -6,8 +6,10 @@ package parse
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strconv"
@@ -35,13 +37,37 @@ const (
 	TypeBind = "bind"
 	// TypeTmpfs is the type for mounting tmpfs
 	TypeTmpfs = "tmpfs"
+	// BuildArgProviderFile is the --build-arg value prefix that reads the
+	// argument's value from a file.
+	BuildArgProviderFile = "file://"
+	// BuildArgProviderEnv is the --build-arg value prefix that reads the
+	// argument's value from an environment variable.
+	BuildArgProviderEnv = "env://"
+	// BuildArgProviderExec is the --build-arg value prefix that reads the
+	// argument's value from the output of a command.
+	BuildArgProviderExec = "exec://"
 )
 
 var (
-	errBadMntOption  = errors.Errorf("invalid mount option")
-	errDuplicateDest = errors.Errorf("duplicate mount destination")
-	optionArgError   = errors.Errorf("must provide an argument for option")
-	noDestError      = errors.Errorf("must set volume destination")
+	// ErrBadMntOption is the base error wrapped by Volumes/GetVolumes/GetBindMount/GetTmpfsMount
+	// when a --volume or --mount option isn't recognized.  Callers which need to distinguish
+	// this failure from other parsing errors can compare against it with errors.Cause().
+	ErrBadMntOption = errors.Errorf("invalid mount option")
+	// ErrDuplicateDest is the base error wrapped by Volumes/GetVolumes/GetBindMount/GetTmpfsMount
+	// when two --volume/--mount arguments specify the same destination in the container.
+	ErrDuplicateDest = errors.Errorf("duplicate mount destination")
+	// ErrBadOptionArg is the base error wrapped by GetBindMount/GetTmpfsMount when a --mount
+	// key=value pair is missing the value half.
+	ErrBadOptionArg = errors.Errorf("must provide an argument for option")
+	// ErrNoDestination is the base error wrapped by GetBindMount/GetTmpfsMount when a --mount
+	// argument doesn't include a target/destination.
+	ErrNoDestination = errors.Errorf("must set volume destination")
+	// ErrBadDeviceMode is the base error wrapped by Device/ParseDevice when a --device argument's
+	// cgroup permissions aren't a combination of "r", "w", and "m".
+	ErrBadDeviceMode = errors.Errorf("invalid device mode")
+	// ErrBadDeviceSpecification is the base error wrapped by Device/ParseDevice when a --device
+	// argument doesn't match any of the accepted forms.
+	ErrBadDeviceSpecification = errors.Errorf("invalid device specification")
 )
 
 // CommonBuildOptions parses the build options from the bud cli
@@ -126,25 +152,29 @@ func CommonBuildOptions(c *cobra.Command) (*define.CommonBuildOptions, error) {
 	}
 
 	secrets, _ := c.Flags().GetStringArray("secret")
+	readOnly, _ := c.Flags().GetBool("read-only")
+	hostContainersInternal, _ := c.Flags().GetBool("host-containers-internal")
 
 	commonOpts := &define.CommonBuildOptions{
-		AddHost:      addHost,
-		CPUPeriod:    cpuPeriod,
-		CPUQuota:     cpuQuota,
-		CPUSetCPUs:   c.Flag("cpuset-cpus").Value.String(),
-		CPUSetMems:   c.Flag("cpuset-mems").Value.String(),
-		CPUShares:    cpuShares,
-		CgroupParent: c.Flag("cgroup-parent").Value.String(),
-		DNSOptions:   dnsOptions,
-		DNSSearch:    dnsSearch,
-		DNSServers:   dnsServers,
-		HTTPProxy:    httpProxy,
-		Memory:       memoryLimit,
-		MemorySwap:   memorySwap,
-		ShmSize:      c.Flag("shm-size").Value.String(),
-		Ulimit:       ulimit,
-		Volumes:      volumes,
-		Secrets:      secrets,
+		AddHost:                   addHost,
+		AddHostContainersInternal: hostContainersInternal,
+		CPUPeriod:                 cpuPeriod,
+		CPUQuota:                  cpuQuota,
+		CPUSetCPUs:                c.Flag("cpuset-cpus").Value.String(),
+		CPUSetMems:                c.Flag("cpuset-mems").Value.String(),
+		CPUShares:                 cpuShares,
+		CgroupParent:              c.Flag("cgroup-parent").Value.String(),
+		DNSOptions:                dnsOptions,
+		DNSSearch:                 dnsSearch,
+		DNSServers:                dnsServers,
+		HTTPProxy:                 httpProxy,
+		Memory:                    memoryLimit,
+		MemorySwap:                memorySwap,
+		ReadOnly:                  readOnly,
+		ShmSize:                   c.Flag("shm-size").Value.String(),
+		Ulimit:                    ulimit,
+		Volumes:                   volumes,
+		Secrets:                   secrets,
 	}
 	securityOpts, _ := c.Flags().GetStringArray("security-opt")
 	if err := parseSecurityOpts(securityOpts, commonOpts); err != nil {
@@ -153,6 +183,24 @@ func CommonBuildOptions(c *cobra.Command) (*define.CommonBuildOptions, error) {
 	return commonOpts, nil
 }
 
+// SecurityOptions parses --security-opt flags for commands, such as run,
+// that don't otherwise accept the full set of CommonBuildOptions, returning
+// overrides for the apparmor profile and seccomp profile path to use for
+// that invocation only.  If no --security-opt flags were given, both
+// return values are empty, so that callers fall back to whatever was
+// configured for the builder.
+func SecurityOptions(c *cobra.Command) (apparmorProfile, seccompProfilePath string, err error) {
+	securityOpts, err := c.Flags().GetStringArray("security-opt")
+	if err != nil || len(securityOpts) == 0 {
+		return "", "", err
+	}
+	commonOpts := &define.CommonBuildOptions{}
+	if err := parseSecurityOpts(securityOpts, commonOpts); err != nil {
+		return "", "", err
+	}
+	return commonOpts.ApparmorProfile, commonOpts.SeccompProfilePath, nil
+}
+
 func parseSecurityOpts(securityOpts []string, commonOpts *define.CommonBuildOptions) error {
 	for _, opt := range securityOpts {
 		if opt == "no-new-privileges" {
@@ -245,7 +293,7 @@ func getVolumeMounts(volumes []string) (map[string]specs.Mount, error) {
 			return nil, err
 		}
 		if _, ok := finalVolumeMounts[volumeMount.Destination]; ok {
-			return nil, errors.Wrapf(errDuplicateDest, volumeMount.Destination)
+			return nil, errors.Wrapf(ErrDuplicateDest, volumeMount.Destination)
 		}
 		finalVolumeMounts[volumeMount.Destination] = volumeMount
 	}
@@ -264,7 +312,7 @@ func GetVolumes(volumes []string, mounts []string) ([]specs.Mount, error) {
 	}
 	for dest, mount := range volumeMounts {
 		if _, ok := unifiedMounts[dest]; ok {
-			return nil, errors.Wrapf(errDuplicateDest, dest)
+			return nil, errors.Wrapf(ErrDuplicateDest, dest)
 		}
 		unifiedMounts[dest] = mount
 	}
@@ -308,7 +356,7 @@ func getMounts(mounts []string) (map[string]specs.Mount, error) {
 				return nil, err
 			}
 			if _, ok := finalMounts[mount.Destination]; ok {
-				return nil, errors.Wrapf(errDuplicateDest, mount.Destination)
+				return nil, errors.Wrapf(ErrDuplicateDest, mount.Destination)
 			}
 			finalMounts[mount.Destination] = mount
 		case TypeTmpfs:
@@ -317,7 +365,7 @@ func getMounts(mounts []string) (map[string]specs.Mount, error) {
 				return nil, err
 			}
 			if _, ok := finalMounts[mount.Destination]; ok {
-				return nil, errors.Wrapf(errDuplicateDest, mount.Destination)
+				return nil, errors.Wrapf(ErrDuplicateDest, mount.Destination)
 			}
 			finalMounts[mount.Destination] = mount
 		default:
@@ -353,12 +401,12 @@ func GetBindMount(args []string) (specs.Mount, error) {
 			newMount.Options = append(newMount.Options, kv[0])
 		case "bind-propagation":
 			if len(kv) == 1 {
-				return newMount, errors.Wrapf(optionArgError, kv[0])
+				return newMount, errors.Wrapf(ErrBadOptionArg, kv[0])
 			}
 			newMount.Options = append(newMount.Options, kv[1])
 		case "src", "source":
 			if len(kv) == 1 {
-				return newMount, errors.Wrapf(optionArgError, kv[0])
+				return newMount, errors.Wrapf(ErrBadOptionArg, kv[0])
 			}
 			if err := ValidateVolumeHostDir(kv[1]); err != nil {
 				return newMount, err
@@ -367,7 +415,7 @@ func GetBindMount(args []string) (specs.Mount, error) {
 			setSource = true
 		case "target", "dst", "destination":
 			if len(kv) == 1 {
-				return newMount, errors.Wrapf(optionArgError, kv[0])
+				return newMount, errors.Wrapf(ErrBadOptionArg, kv[0])
 			}
 			if err := ValidateVolumeCtrDir(kv[1]); err != nil {
 				return newMount, err
@@ -379,12 +427,12 @@ func GetBindMount(args []string) (specs.Mount, error) {
 			// and can thus be safely ignored.
 			// See also the handling of the equivalent "delegated" and "cached" in ValidateVolumeOpts
 		default:
-			return newMount, errors.Wrapf(errBadMntOption, kv[0])
+			return newMount, errors.Wrapf(ErrBadMntOption, kv[0])
 		}
 	}
 
 	if !setDest {
-		return newMount, noDestError
+		return newMount, ErrNoDestination
 	}
 
 	if !setSource {
@@ -419,19 +467,19 @@ func GetTmpfsMount(args []string) (specs.Mount, error) {
 			newMount.Options = append(newMount.Options, "ro")
 		case "tmpfs-mode":
 			if len(kv) == 1 {
-				return newMount, errors.Wrapf(optionArgError, kv[0])
+				return newMount, errors.Wrapf(ErrBadOptionArg, kv[0])
 			}
 			newMount.Options = append(newMount.Options, fmt.Sprintf("mode=%s", kv[1]))
 		case "tmpfs-size":
 			if len(kv) == 1 {
-				return newMount, errors.Wrapf(optionArgError, kv[0])
+				return newMount, errors.Wrapf(ErrBadOptionArg, kv[0])
 			}
 			newMount.Options = append(newMount.Options, fmt.Sprintf("size=%s", kv[1]))
 		case "src", "source":
 			return newMount, errors.Errorf("source is not supported with tmpfs mounts")
 		case "target", "dst", "destination":
 			if len(kv) == 1 {
-				return newMount, errors.Wrapf(optionArgError, kv[0])
+				return newMount, errors.Wrapf(ErrBadOptionArg, kv[0])
 			}
 			if err := ValidateVolumeCtrDir(kv[1]); err != nil {
 				return newMount, err
@@ -439,12 +487,12 @@ func GetTmpfsMount(args []string) (specs.Mount, error) {
 			newMount.Destination = kv[1]
 			setDest = true
 		default:
-			return newMount, errors.Wrapf(errBadMntOption, kv[0])
+			return newMount, errors.Wrapf(ErrBadMntOption, kv[0])
 		}
 	}
 
 	if !setDest {
-		return newMount, noDestError
+		return newMount, ErrNoDestination
 	}
 
 	return newMount, nil
@@ -835,15 +883,33 @@ func IDMappingOptions(c *cobra.Command, isolation define.Isolation) (usernsOptio
 		Name: string(specs.UserNamespace),
 		Host: len(uidmap) == 0 && len(gidmap) == 0,
 	}
+	var autoUserNs bool
+	var autoUserNsSize uint32
 	// If the user specifically requested that we either use or don't use
 	// user namespaces, override that default.
 	if c.Flag("userns").Changed {
 		how := c.Flag("userns").Value.String()
-		switch how {
-		case "", "container", "private":
+		switch {
+		case how == "", how == "container", how == "private":
 			usernsOption.Host = false
-		case "host":
+		case how == "host":
 			usernsOption.Host = true
+		case how == "auto" || strings.HasPrefix(how, "auto:"):
+			usernsOption.Host = false
+			autoUserNs = true
+			if rest := strings.TrimPrefix(how, "auto:"); rest != how {
+				for _, opt := range strings.Split(rest, ",") {
+					if !strings.HasPrefix(opt, "size=") {
+						return nil, nil, errors.Errorf("unrecognized --userns=auto option %q", opt)
+					}
+					size := strings.TrimPrefix(opt, "size=")
+					parsedSize, err := strconv.ParseUint(size, 10, 32)
+					if err != nil {
+						return nil, nil, errors.Wrapf(err, "parsing --userns=auto size %q", size)
+					}
+					autoUserNsSize = uint32(parsedSize)
+				}
+			}
 		default:
 			how = strings.TrimPrefix(how, "ns:")
 			if _, err := os.Stat(how); err != nil {
@@ -872,6 +938,8 @@ func IDMappingOptions(c *cobra.Command, isolation define.Isolation) (usernsOptio
 		HostGIDMapping: usernsOption.Host,
 		UIDMap:         uidmap,
 		GIDMap:         gidmap,
+		AutoUserNs:     autoUserNs,
+		AutoUserNsSize: autoUserNsSize,
 	}, nil
 }
 
@@ -990,10 +1058,11 @@ func IsolationOption(isolation string) (define.Isolation, error) {
 
 // Device parses device mapping string to a src, dest & permissions string
 // Valid values for device look like:
-//    '/dev/sdc"
-//    '/dev/sdc:/dev/xvdc"
-//    '/dev/sdc:/dev/xvdc:rwm"
-//    '/dev/sdc:rm"
+//
+//	'/dev/sdc"
+//	'/dev/sdc:/dev/xvdc"
+//	'/dev/sdc:/dev/xvdc:rwm"
+//	'/dev/sdc:rm"
 func Device(device string) (string, string, string, error) {
 	src := ""
 	dst := ""
@@ -1002,7 +1071,7 @@ func Device(device string) (string, string, string, error) {
 	switch len(arr) {
 	case 3:
 		if !isValidDeviceMode(arr[2]) {
-			return "", "", "", errors.Errorf("invalid device mode: %s", arr[2])
+			return "", "", "", errors.Wrapf(ErrBadDeviceMode, "%s", arr[2])
 		}
 		permissions = arr[2]
 		fallthrough
@@ -1011,7 +1080,7 @@ func Device(device string) (string, string, string, error) {
 			permissions = arr[1]
 		} else {
 			if len(arr[1]) == 0 || arr[1][0] != '/' {
-				return "", "", "", errors.Errorf("invalid device mode: %s", arr[1])
+				return "", "", "", errors.Wrapf(ErrBadDeviceMode, "%s", arr[1])
 			}
 			dst = arr[1]
 		}
@@ -1023,7 +1092,7 @@ func Device(device string) (string, string, string, error) {
 		}
 		fallthrough
 	default:
-		return "", "", "", errors.Errorf("invalid device specification: %s", device)
+		return "", "", "", errors.Wrapf(ErrBadDeviceSpecification, "%s", device)
 	}
 
 	if dst == "" {
@@ -1032,6 +1101,26 @@ func Device(device string) (string, string, string, error) {
 	return src, dst, permissions, nil
 }
 
+// ParsedDevice holds the results of parsing a --device argument, for
+// callers which would rather work with named fields than remember the
+// order in which Device returns its three strings.
+type ParsedDevice struct {
+	Source      string
+	Destination string
+	Permissions string
+}
+
+// ParseDevice is a typed wrapper around Device: it accepts the same values
+// and reports the same errors, but returns them in a ParsedDevice instead of
+// as three separate strings.
+func ParseDevice(device string) (ParsedDevice, error) {
+	src, dst, permissions, err := Device(device)
+	if err != nil {
+		return ParsedDevice{}, err
+	}
+	return ParsedDevice{Source: src, Destination: dst, Permissions: permissions}, nil
+}
+
 // isValidDeviceMode checks if the mode for device is valid or not.
 // isValid mode is a composition of r (read), w (write), and m (mknod).
 func isValidDeviceMode(mode string) bool {
@@ -1059,6 +1148,37 @@ func GetTempDir() string {
 	return "/var/tmp"
 }
 
+// MinimumTmpDirSpace is the amount of free space we insist on seeing in the
+// directory used for staging blobs (pulled layers, layers being pushed or
+// committed, and files fetched by ADD/COPY) before letting a command start,
+// so that running out of room is reported immediately instead of after a
+// build has already spent time downloading and unpacking layers.
+const MinimumTmpDirSpace = 100 * 1024 * 1024 // 100MB
+
+// ValidateTmpDir checks that dir exists and is a directory, and, where free
+// space can be determined, that it has at least MinimumTmpDirSpace bytes
+// free.  It's meant to be called once, early, on whichever directory
+// GetTempDir() would otherwise leave callers to discover is full partway
+// through staging a blob.
+func ValidateTmpDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return errors.Wrapf(err, "error checking temporary directory %q", dir)
+	}
+	if !info.IsDir() {
+		return errors.Errorf("temporary directory %q is not a directory", dir)
+	}
+	available, err := AvailableSpace(dir)
+	if err != nil {
+		logrus.Debugf("not checking free space in temporary directory %q: %v", dir, err)
+		return nil
+	}
+	if available < MinimumTmpDirSpace {
+		return errors.Errorf("only %d bytes free in temporary directory %q, need at least %d: free up space or pass --tmpdir with a directory that has more room", available, dir, MinimumTmpDirSpace)
+	}
+	return nil
+}
+
 // Secrets parses the --secret flag
 func Secrets(secrets []string) (map[string]string, error) {
 	parsed := make(map[string]string)
@@ -1092,3 +1212,46 @@ func Secrets(secrets []string) (map[string]string, error) {
 	}
 	return parsed, nil
 }
+
+// IsBuildArgProvider returns true if value uses one of the recognized
+// provider schemes ("file://", "env://", or "exec://") understood by
+// ResolveBuildArgProvider, rather than being a literal value.
+func IsBuildArgProvider(value string) bool {
+	return strings.HasPrefix(value, BuildArgProviderFile) ||
+		strings.HasPrefix(value, BuildArgProviderEnv) ||
+		strings.HasPrefix(value, BuildArgProviderExec)
+}
+
+// ResolveBuildArgProvider fetches a --build-arg value from the provider
+// named by one of the recognized schemes: "file://path" reads the named
+// file, "env://NAME" reads the named environment variable from the
+// buildah process's own environment, and "exec://command" runs "command"
+// with the shell and captures its standard output.  In each case trailing
+// newlines are trimmed.  Callers should avoid logging the resolved value,
+// since providers are commonly used to inject secrets or credentials.
+func ResolveBuildArgProvider(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, BuildArgProviderFile):
+		path := strings.TrimPrefix(value, BuildArgProviderFile)
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", errors.Wrapf(err, "error reading build-arg value from %q", path)
+		}
+		return strings.TrimRight(string(contents), "\n"), nil
+	case strings.HasPrefix(value, BuildArgProviderEnv):
+		name := strings.TrimPrefix(value, BuildArgProviderEnv)
+		val, ok := os.LookupEnv(name)
+		if !ok {
+			return "", errors.Errorf("build-arg provider %q: environment variable %q is not set", value, name)
+		}
+		return val, nil
+	case strings.HasPrefix(value, BuildArgProviderExec):
+		command := strings.TrimPrefix(value, BuildArgProviderExec)
+		output, err := exec.Command("/bin/sh", "-c", command).Output()
+		if err != nil {
+			return "", errors.Wrapf(err, "error running command for build-arg provider %q", value)
+		}
+		return strings.TrimRight(string(output), "\n"), nil
+	}
+	return value, nil
+}
@@ -10,8 +10,19 @@ import (
 	"github.com/containers/storage/pkg/unshare"
 	"github.com/opencontainers/runc/libcontainer/devices"
 	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
 )
 
+// AvailableSpace returns the number of bytes free in the filesystem holding
+// dir.
+func AvailableSpace(dir string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return 0, errors.Wrapf(err, "error checking free space in %q", dir)
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
+
 func DeviceFromPath(device string) (define.ContainerDevices, error) {
 	var devs define.ContainerDevices
 	src, dst, permissions, err := Device(device)
@@ -14,3 +14,9 @@ func getDefaultProcessLimits() []string {
 func DeviceFromPath(device string) (define.ContainerDevices, error) {
 	return nil, errors.Errorf("devices not supported")
 }
+
+// AvailableSpace returns the number of bytes free in the filesystem holding
+// dir.
+func AvailableSpace(dir string) (uint64, error) {
+	return 0, errors.Errorf("checking free space is not supported on this platform")
+}
@@ -0,0 +1,23 @@
+// Package pod provides a lightweight way for several working containers to
+// share a single network namespace, so that a multi-container setup can be
+// assembled interactively (e.g. one container per service) and exercised
+// together before any of the images involved are committed.
+//
+// Grouping is purely local to the machine running buildah: a "pod" is
+// nothing more than a name for a persistent network namespace that gets
+// created the first time it's referenced and can then be joined by any
+// number of working containers via their --net/--network flag.
+package pod
+
+import (
+	"path/filepath"
+)
+
+// NetNSPath returns the path at which the persistent network namespace for
+// the pod named name is (or would be) bind-mounted under runRoot. runRoot
+// should be the same run root that the buildah storage is using, so that
+// the namespace is cleaned up along with everything else when the run root
+// is reset.
+func NetNSPath(runRoot, name string) string {
+	return filepath.Join(runRoot, "buildah-pods", name, "netns")
+}
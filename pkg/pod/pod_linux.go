@@ -0,0 +1,69 @@
+package pod
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/containers/storage/pkg/reexec"
+	"github.com/pkg/errors"
+	"golang.org/x/sys/unix"
+)
+
+const createNetNSCommand = "buildah-pod-create-netns"
+
+func init() {
+	reexec.Register(createNetNSCommand, createNetNSMain)
+}
+
+// EnsureNetNS creates the persistent network namespace for the pod named
+// name under runRoot, if one doesn't already exist, and returns its path.
+// The returned path can be joined by other working containers by passing
+// "ns:<path>" as their network namespace.
+func EnsureNetNS(runRoot, name string) (string, error) {
+	nsPath := NetNSPath(runRoot, name)
+	if _, err := os.Stat(nsPath); err == nil {
+		return nsPath, nil
+	} else if !os.IsNotExist(err) {
+		return "", errors.Wrapf(err, "checking for existing network namespace for pod %q", name)
+	}
+	if err := os.MkdirAll(filepath.Dir(nsPath), 0700); err != nil {
+		return "", errors.Wrapf(err, "creating directory for pod %q", name)
+	}
+	f, err := os.OpenFile(nsPath, os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", errors.Wrapf(err, "creating network namespace file for pod %q", name)
+	}
+	f.Close()
+
+	cmd := reexec.Command(createNetNSCommand, nsPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(nsPath)
+		return "", errors.Wrapf(err, "creating network namespace for pod %q: %s", name, string(output))
+	}
+	return nsPath, nil
+}
+
+// createNetNSMain is run in a re-exec'ed child.  It unshares a new network
+// namespace and bind-mounts it at the path given as its only argument, so
+// that the namespace outlives this short-lived process and can be joined
+// later by other containers.
+func createNetNSMain() {
+	if len(os.Args) != 2 {
+		fatal(errors.New("expected exactly one argument, the path to bind-mount the namespace at"))
+	}
+	nsPath := os.Args[1]
+
+	runtime.LockOSThread()
+	if err := unix.Unshare(unix.CLONE_NEWNET); err != nil {
+		fatal(errors.Wrapf(err, "unsharing network namespace"))
+	}
+	if err := unix.Mount("/proc/self/ns/net", nsPath, "", unix.MS_BIND, ""); err != nil {
+		fatal(errors.Wrapf(err, "bind-mounting network namespace at %q", nsPath))
+	}
+}
+
+func fatal(err error) {
+	os.Stderr.WriteString(err.Error() + "\n")
+	os.Exit(1)
+}
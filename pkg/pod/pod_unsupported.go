@@ -0,0 +1,12 @@
+// +build !linux
+
+package pod
+
+import (
+	"github.com/pkg/errors"
+)
+
+// EnsureNetNS is not supported on this platform.
+func EnsureNetNS(runRoot, name string) (string, error) {
+	return "", errors.New("pods are not supported on this platform")
+}
@@ -0,0 +1,92 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Vulnerability describes a single issue reported by an external scanner.
+type Vulnerability struct {
+	ID       string `json:"id"`
+	Severity string `json:"severity"`
+	Package  string `json:"package,omitempty"`
+	Version  string `json:"version,omitempty"`
+}
+
+// Result is the parsed output of a scanner run.
+type Result struct {
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+}
+
+// severityOrder ranks the severity names scanners are expected to report,
+// from least to most severe, using the naming convention shared by grype
+// and trivy.
+var severityOrder = []string{"unknown", "negligible", "low", "medium", "high", "critical"}
+
+func severityRank(severity string) int {
+	s := strings.ToLower(severity)
+	for i, name := range severityOrder {
+		if name == s {
+			return i
+		}
+	}
+	return 0
+}
+
+// Options configures how an external scanner is invoked.
+type Options struct {
+	// Command is the path to, or name of, the scanner executable to run.
+	Command string
+	// Args are additional arguments to pass to Command before the image
+	// reference being scanned.
+	Args []string
+}
+
+// Run execs the configured scanner against imageRef and parses its output.
+//
+// The scanner is invoked as "Command Args... imageRef" and is expected to
+// write a JSON document of the form
+// {"vulnerabilities": [{"id", "severity", "package", "version"}, ...]} to
+// its standard output. Neither grype nor trivy emit that exact shape
+// natively, but both support custom output templates, so wrapping either
+// one in a small template or shim script is the intended way to plug it
+// into this contract.
+func Run(ctx context.Context, imageRef string, options Options) (*Result, error) {
+	if options.Command == "" {
+		return nil, errors.New("no scanner command configured")
+	}
+	args := append(append([]string{}, options.Args...), imageRef)
+	cmd := exec.CommandContext(ctx, options.Command, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.Wrapf(err, "error running scanner %q: %s", options.Command, strings.TrimSpace(stderr.String()))
+	}
+	var result Result
+	if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+		return nil, errors.Wrapf(err, "error parsing output of scanner %q", options.Command)
+	}
+	return &result, nil
+}
+
+// ExceedsSeverity returns true if any vulnerability in the result is at or
+// above the named threshold severity ("critical", "high", "medium", "low",
+// "negligible", or "unknown").
+func (r *Result) ExceedsSeverity(threshold string) bool {
+	if threshold == "" {
+		return false
+	}
+	minRank := severityRank(threshold)
+	for _, v := range r.Vulnerabilities {
+		if severityRank(v.Severity) >= minRank {
+			return true
+		}
+	}
+	return false
+}
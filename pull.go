@@ -26,6 +26,10 @@ type PullOptions struct {
 	// ReportWriter is an io.Writer which will be used to log the writing
 	// of the new image.
 	ReportWriter io.Writer
+	// Progress, if set, receives structured notifications of the progress
+	// of copying blobs while pulling the image, in addition to whatever
+	// is written to ReportWriter.
+	Progress define.ProgressReporter
 	// Store is the local storage store which holds the source image.
 	Store storage.Store
 	// github.com/containers/image/types SystemContext to hold credentials
@@ -64,6 +68,10 @@ func Pull(ctx context.Context, imageName string, options PullOptions) (imageID s
 	libimageOptions.AllTags = options.AllTags
 	libimageOptions.RetryDelay = &options.RetryDelay
 
+	progressChan, stopProgress := startBlobProgress(options.Progress)
+	defer stopProgress()
+	libimageOptions.Progress = progressChan
+
 	if options.MaxRetries > 0 {
 		retries := uint(options.MaxRetries)
 		libimageOptions.MaxRetries = &retries
@@ -6,6 +6,7 @@ import (
 	"io"
 	"time"
 
+	"github.com/containers/buildah/define"
 	"github.com/containers/buildah/pkg/blobcache"
 	"github.com/containers/common/libimage"
 	"github.com/containers/image/v5/docker/reference"
@@ -35,6 +36,10 @@ type PushOptions struct {
 	// ReportWriter is an io.Writer which will be used to log the writing
 	// of the new image.
 	ReportWriter io.Writer
+	// Progress, if set, receives structured notifications of the progress
+	// of copying blobs while pushing the image, in addition to whatever
+	// is written to ReportWriter.
+	Progress define.ProgressReporter
 	// Store is the local storage store which holds the source image.
 	Store storage.Store
 	// github.com/containers/image/types SystemContext to hold credentials
@@ -90,6 +95,10 @@ func Push(ctx context.Context, image string, dest types.ImageReference, options
 		libimageOptions.Writer = nil
 	}
 
+	progressChan, stopProgress := startBlobProgress(options.Progress)
+	defer stopProgress()
+	libimageOptions.Progress = progressChan
+
 	if options.BlobDirectory != "" {
 		compress := types.PreserveOriginal
 		if options.Compression == archive.Gzip {
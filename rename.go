@@ -0,0 +1,27 @@
+package buildah
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Rename changes the name by which the working container is known, both in
+// local storage and in the Builder's own metadata, after confirming that no
+// other container is already using the new name.
+func (b *Builder) Rename(name string) error {
+	oldName := b.Container
+	if oldName == name {
+		return errors.Errorf("renaming a container with the same name as its current name")
+	}
+	containers, err := b.store.Containers()
+	if err != nil {
+		return errors.Wrapf(err, "error reading list of containers")
+	}
+	if containerNameExist(name, containers) {
+		return errors.Errorf("the container name %q is already in use by another container", name)
+	}
+	if err := b.store.SetNames(b.ContainerID, []string{name}); err != nil {
+		return errors.Wrapf(err, "error renaming container %q to the name %q", oldName, name)
+	}
+	b.Container = name
+	return b.Save()
+}
@@ -1,6 +1,7 @@
 package buildah
 
 import (
+	"context"
 	"fmt"
 	"io"
 
@@ -71,6 +72,10 @@ const (
 
 // RunOptions can be used to alter how a command is run in the container.
 type RunOptions struct {
+	// Context is used to abort the run early, killing the command's
+	// process if it's still running when the context is canceled.  If
+	// left unset, context.Background() is used.
+	Context context.Context `json:"-"`
 	// Logger is the logrus logger to write log messages with
 	Logger *logrus.Logger `json:"-"`
 	// Hostname is the hostname we set for the running container.
@@ -122,6 +127,13 @@ type RunOptions struct {
 	// a pseudo-terminal, if we create one, and Stdin/Stdout/Stderr aren't
 	// connected to a terminal.
 	TerminalSize *specs.Box
+	// Resize is a channel which callers can use to notify Run of changes
+	// to the size of the terminal, when Terminal causes one to be
+	// created.  Each Box received is applied to the running command's
+	// pseudoterminal.  It's ignored if Run doesn't end up creating a
+	// pseudoterminal, and, for now, under IsolationChroot, whose child
+	// processes don't have a channel back to Run to receive updates.
+	Resize <-chan specs.Box `json:"-"`
 	// The stdin/stdout/stderr descriptors to use.  If set to nil, the
 	// corresponding files in the "os" package are used as defaults.
 	Stdin  io.Reader `json:"-"`
@@ -142,4 +154,29 @@ type RunOptions struct {
 	// RunMounts are mounts for this run. RunMounts for this run
 	// will not show up in subsequent runs.
 	RunMounts []string
+	// SeccompProfilePath is the pathname of a seccomp profile to use in
+	// place of the one configured for the builder (e.g. via
+	// --security-opt seccomp=... at build time).
+	SeccompProfilePath string
+	// ApparmorProfile is the name of an apparmor profile to use in place
+	// of the one configured for the builder (e.g. via --security-opt
+	// apparmor=... at build time).
+	ApparmorProfile string
+	// ReadOnly causes the container's root filesystem to be mounted
+	// read-only for the duration of this Run(), in addition to (and not
+	// instead of) any read-only setting configured for the builder as a
+	// whole via CommonBuildOptions.ReadOnly.
+	ReadOnly bool
+	// ReadOnlyTmpfs, if ReadOnly is set, causes tmpfs mounts to be added
+	// over /tmp, /run, and /var/tmp so that steps which need to write
+	// scratch data to those conventional locations still work.
+	ReadOnlyTmpfs bool
+	// GroupAdd is a list of supplemental groups to add, in addition to
+	// the ones which come from the container's /etc/group entries, to
+	// the process's group list.  A group can be given as a numeric GID,
+	// the name of a group in the container's /etc/group, or the special
+	// value "keep-groups", which (in rootless mode, when supported by
+	// the isolation type) preserves the calling user's supplemental
+	// group list instead of dropping it.
+	GroupAdd []string
 }
@@ -1,3 +1,4 @@
+//go:build linux
 // +build linux
 
 package buildah
@@ -25,7 +26,9 @@ import (
 	"github.com/containers/buildah/chroot"
 	"github.com/containers/buildah/copier"
 	"github.com/containers/buildah/define"
+	"github.com/containers/buildah/pkg/chrootuser"
 	"github.com/containers/buildah/pkg/overlay"
+	"github.com/containers/buildah/pkg/parse"
 	"github.com/containers/buildah/util"
 	"github.com/containers/common/pkg/capabilities"
 	"github.com/containers/common/pkg/chown"
@@ -161,7 +164,17 @@ func (b *Builder) Run(command []string, options RunOptions) error {
 		return err
 	}
 
-	g.SetProcessApparmorProfile(b.CommonBuildOpts.ApparmorProfile)
+	apparmorProfile := b.CommonBuildOpts.ApparmorProfile
+	if options.ApparmorProfile != "" {
+		apparmorProfile = options.ApparmorProfile
+	}
+	g.SetProcessApparmorProfile(apparmorProfile)
+
+	readOnly := b.CommonBuildOpts.ReadOnly || options.ReadOnly
+	if readOnly {
+		g.SetRootReadonly(true)
+	}
+	readOnlyTmpfs := b.CommonBuildOpts.ReadOnly || (options.ReadOnly && options.ReadOnlyTmpfs)
 
 	// Now grab the spec from the generator.  Set the generator to nil so that future contributors
 	// will quickly be able to tell that they're supposed to be modifying the spec directly from here.
@@ -171,7 +184,11 @@ func (b *Builder) Run(command []string, options RunOptions) error {
 	// Set the seccomp configuration using the specified profile name.  Some syscalls are
 	// allowed if certain capabilities are to be granted (example: CAP_SYS_CHROOT and chroot),
 	// so we sorted out the capabilities lists first.
-	if err = setupSeccomp(spec, b.CommonBuildOpts.SeccompProfilePath); err != nil {
+	seccompProfilePath := b.CommonBuildOpts.SeccompProfilePath
+	if options.SeccompProfilePath != "" {
+		seccompProfilePath = options.SeccompProfilePath
+	}
+	if err = setupSeccomp(spec, seccompProfilePath); err != nil {
 		return err
 	}
 
@@ -196,7 +213,7 @@ func (b *Builder) Run(command []string, options RunOptions) error {
 	volumes := b.Volumes()
 
 	if !contains(volumes, "/etc/hosts") {
-		hostFile, err := b.generateHosts(path, spec.Hostname, b.CommonBuildOpts.AddHost, rootIDPair)
+		hostFile, err := b.generateHosts(path, spec.Hostname, b.CommonBuildOpts.AddHost, b.CommonBuildOpts.AddHostContainersInternal, rootIDPair)
 		if err != nil {
 			return err
 		}
@@ -247,7 +264,19 @@ rootless=%d
 		bindFiles["/run/.containerenv"] = containerenvPath
 	}
 
-	runMountTargets, err := b.setupMounts(mountPoint, spec, path, options.Mounts, bindFiles, volumes, b.CommonBuildOpts.Volumes, b.CommonBuildOpts.ShmSize, namespaceOptions, options.Secrets, options.RunMounts)
+	optionMounts := options.Mounts
+	if readOnlyTmpfs {
+		for _, dir := range []string{"/tmp", "/run", "/var/tmp"} {
+			optionMounts = append(optionMounts, specs.Mount{
+				Destination: dir,
+				Type:        parse.TypeTmpfs,
+				Source:      parse.TypeTmpfs,
+				Options:     []string{"rprivate", "nosuid", "nodev", "mode=1777"},
+			})
+		}
+	}
+
+	runMountTargets, err := b.setupMounts(mountPoint, spec, path, optionMounts, bindFiles, volumes, b.CommonBuildOpts.Volumes, b.CommonBuildOpts.ShmSize, namespaceOptions, options.Secrets, options.RunMounts)
 	if err != nil {
 		return errors.Wrapf(err, "error resolving mountpoints for container %q", b.ContainerID)
 	}
@@ -629,7 +658,7 @@ func (b *Builder) addNetworkConfig(rdir, hostPath string, chownOpts *idtools.IDP
 }
 
 // generateHosts creates a containers hosts file
-func (b *Builder) generateHosts(rdir, hostname string, addHosts []string, chownOpts *idtools.IDPair) (string, error) {
+func (b *Builder) generateHosts(rdir, hostname string, addHosts []string, addHostContainersInternal bool, chownOpts *idtools.IDPair) (string, error) {
 	hostPath := "/etc/hosts"
 	stat, err := os.Stat(hostPath)
 	if err != nil {
@@ -657,6 +686,14 @@ func (b *Builder) generateHosts(rdir, hostname string, addHosts []string, chownO
 		hosts.Write([]byte(fmt.Sprintf("%s\t%s\n", values[1], values[0])))
 	}
 
+	if addHostContainersInternal && unshare.IsRootless() {
+		// Rootless builds reach the network through slirp4netns, whose
+		// built-in gateway address is fixed at 10.0.2.2; steps which need
+		// to reach a service (e.g. a registry) bound to localhost on the
+		// build host can use this name instead of hard-coding that address.
+		hosts.Write([]byte("10.0.2.2\thost.containers.internal\n"))
+	}
+
 	if hostname != "" {
 		hosts.Write([]byte(fmt.Sprintf("127.0.0.1   %s\n", hostname)))
 		hosts.Write([]byte(fmt.Sprintf("::1         %s\n", hostname)))
@@ -702,6 +739,10 @@ func setupTerminal(g *generate.Generator, terminalPolicy TerminalPolicy, termina
 }
 
 func runUsingRuntime(isolation define.Isolation, options RunOptions, configureNetwork bool, configureNetworks, moreCreateArgs []string, spec *specs.Spec, bundlePath, containerName string) (wstatus unix.WaitStatus, err error) {
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	// Lock the caller to a single OS-level thread.
 	runtime.LockOSThread()
 
@@ -887,7 +928,7 @@ func runUsingRuntime(isolation define.Isolation, options RunOptions, configureNe
 
 	// Handle stdio for the container in the background.
 	stdio.Add(1)
-	go runCopyStdio(options.Logger, &stdio, copyPipes, stdioPipe, copyConsole, consoleListener, finishCopy, finishedCopy, spec)
+	go runCopyStdio(options.Logger, &stdio, copyPipes, stdioPipe, copyConsole, consoleListener, finishCopy, finishedCopy, spec, options.Resize)
 
 	// Start the container.
 	logrus.Debugf("Running %q", start.Args)
@@ -935,6 +976,12 @@ func runUsingRuntime(isolation define.Isolation, options RunOptions, configureNe
 		select {
 		case <-finishedCopy:
 			stopped = true
+		case <-ctx.Done():
+			if err2 := kill.Run(); err2 != nil {
+				options.Logger.Infof("error from %s stopping container after context was canceled: %v", runtime, err2)
+			}
+			stopped = true
+			err = ctx.Err()
 		case <-time.After(time.Until(now.Add(100 * time.Millisecond))):
 			continue
 		}
@@ -950,7 +997,7 @@ func runUsingRuntime(isolation define.Isolation, options RunOptions, configureNe
 	// Wait until we finish reading the exit status.
 	reaping.Wait()
 
-	return wstatus, nil
+	return wstatus, err
 }
 
 func runCollectOutput(logger *logrus.Logger, fds, closeBeforeReadingFds []int) string { //nolint:interfacer
@@ -1199,7 +1246,7 @@ func setNonblock(logger *logrus.Logger, fd int, description string, nonblocking
 	return blocked, err
 }
 
-func runCopyStdio(logger *logrus.Logger, stdio *sync.WaitGroup, copyPipes bool, stdioPipe [][]int, copyConsole bool, consoleListener *net.UnixListener, finishCopy []int, finishedCopy chan struct{}, spec *specs.Spec) {
+func runCopyStdio(logger *logrus.Logger, stdio *sync.WaitGroup, copyPipes bool, stdioPipe [][]int, copyConsole bool, consoleListener *net.UnixListener, finishCopy []int, finishedCopy chan struct{}, spec *specs.Spec, resize <-chan specs.Box) {
 	defer func() {
 		unix.Close(finishCopy[0])
 		if copyPipes {
@@ -1226,6 +1273,11 @@ func runCopyStdio(logger *logrus.Logger, stdio *sync.WaitGroup, copyPipes bool,
 			return
 		}
 		terminalFD := fd
+		if resize != nil {
+			stopResizing := make(chan struct{})
+			defer close(stopResizing)
+			go runResizeTerminal(logger, terminalFD, resize, stopResizing)
+		}
 		// Input from our stdin, output from the terminal descriptor.
 		relayMap[unix.Stdin] = terminalFD
 		readDesc[unix.Stdin] = "stdin"
@@ -1415,6 +1467,25 @@ func runCopyStdioPassData(copyPipes bool, stdioPipe [][]int, finishCopy []int, r
 	}
 }
 
+// runResizeTerminal reads Box values from resize and applies each one as
+// the new size of the pseudoterminal at terminalFD, until stop is closed.
+func runResizeTerminal(logger *logrus.Logger, terminalFD int, resize <-chan specs.Box, stop <-chan struct{}) {
+	for {
+		select {
+		case box, ok := <-resize:
+			if !ok {
+				return
+			}
+			winsize := &unix.Winsize{Row: uint16(box.Height), Col: uint16(box.Width)}
+			if err := unix.IoctlSetWinsize(terminalFD, unix.TIOCSWINSZ, winsize); err != nil {
+				logger.Warnf("error resizing container pseudoterminal: %v", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
 func runAcceptTerminal(logger *logrus.Logger, consoleListener *net.UnixListener, terminalSize *specs.Box) (int, error) {
 	defer consoleListener.Close()
 	c, err := consoleListener.AcceptUnix()
@@ -1478,9 +1549,6 @@ func runAcceptTerminal(logger *logrus.Logger, consoleListener *net.UnixListener,
 		if err = unix.IoctlSetWinsize(terminalFD, unix.TIOCSWINSZ, winsize); err != nil {
 			logger.Warnf("error setting size of container pseudoterminal: %v", err)
 		}
-		// FIXME - if we're connected to a terminal, we should
-		// be passing the updated terminal size down when we
-		// receive a SIGWINCH.
 	}
 	return terminalFD, nil
 }
@@ -2027,6 +2095,9 @@ func (b *Builder) configureUIDGID(g *generate.Generator, mountPoint string, opti
 	for _, gid := range user.AdditionalGids {
 		g.AddProcessAdditionalGid(gid)
 	}
+	if err := b.addRunGroups(g, mountPoint, options.GroupAdd); err != nil {
+		return "", err
+	}
 
 	// Remove capabilities if not running as root except Bounding set
 	if user.UID != 0 {
@@ -2038,6 +2109,35 @@ func (b *Builder) configureUIDGID(g *generate.Generator, mountPoint string, opti
 	return homeDir, nil
 }
 
+// addRunGroups adds the supplemental groups requested via options.GroupAdd
+// to the generator's process spec, resolving names and the special
+// "keep-groups" value against the container (or, for "keep-groups", the
+// calling process) as appropriate.
+func (b *Builder) addRunGroups(g *generate.Generator, mountPoint string, groupAdd []string) error {
+	for _, group := range groupAdd {
+		if group == "keep-groups" {
+			gids, err := unix.Getgroups()
+			if err != nil {
+				return errors.Wrapf(err, "error reading current supplemental group list")
+			}
+			for _, gid := range gids {
+				g.AddProcessAdditionalGid(uint32(gid))
+			}
+			continue
+		}
+		if gid, err := strconv.ParseUint(group, 10, 32); err == nil {
+			g.AddProcessAdditionalGid(uint32(gid))
+			continue
+		}
+		gid, err := chrootuser.GetGroup(mountPoint, group)
+		if err != nil {
+			return errors.Wrapf(err, "error looking up group %q for --group-add", group)
+		}
+		g.AddProcessAdditionalGid(gid)
+	}
+	return nil
+}
+
 func (b *Builder) configureEnvironment(g *generate.Generator, options RunOptions, defaultEnv []string) {
 	g.ClearProcessEnv()
 
@@ -2132,6 +2232,67 @@ func setupRootlessSpecChanges(spec *specs.Spec, bundleDir string, shmSize string
 	return nil
 }
 
+// runStateInfo records enough information about a "buildah run" invocation
+// that a separate "buildah stats" process can later ask the same OCI
+// runtime for the running container's current state (and, from that, its
+// process ID and cgroup).
+type runStateInfo struct {
+	ContainerName string   `json:"containerName"`
+	BundlePath    string   `json:"bundlePath"`
+	Runtime       string   `json:"runtime"`
+	RuntimeArgs   []string `json:"runtimeArgs"`
+}
+
+// runStateFilePath returns the path of the file in which we record the
+// information that "buildah stats" needs in order to find this container's
+// live runtime state while a "buildah run" is executing.
+func (b *Builder) runStateFilePath() (string, error) {
+	cdir, err := b.store.ContainerDirectory(b.ContainerID)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cdir, "run-state.json"), nil
+}
+
+func (b *Builder) recordRunState(options RunOptions, containerName string, bundlePath string) {
+	statePath, err := b.runStateFilePath()
+	if err != nil {
+		logrus.Debugf("error determining run state file path for container %q: %v", b.ContainerID, err)
+		return
+	}
+	runtime := options.Runtime
+	if runtime == "" {
+		runtime = util.Runtime()
+		if localRuntime := util.FindLocalRuntime(runtime); localRuntime != "" {
+			runtime = localRuntime
+		}
+	}
+	info := runStateInfo{
+		ContainerName: containerName,
+		BundlePath:    bundlePath,
+		Runtime:       runtime,
+		RuntimeArgs:   options.Args,
+	}
+	data, err := json.Marshal(info)
+	if err != nil {
+		logrus.Debugf("error encoding run state for container %q: %v", b.ContainerID, err)
+		return
+	}
+	if err := ioutils.AtomicWriteFile(statePath, data, 0600); err != nil {
+		logrus.Debugf("error recording run state for container %q: %v", b.ContainerID, err)
+	}
+}
+
+func (b *Builder) clearRunState() {
+	statePath, err := b.runStateFilePath()
+	if err != nil {
+		return
+	}
+	if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+		logrus.Debugf("error clearing run state for container %q: %v", b.ContainerID, err)
+	}
+}
+
 func (b *Builder) runUsingRuntimeSubproc(isolation define.Isolation, options RunOptions, configureNetwork bool, configureNetworks, moreCreateArgs []string, spec *specs.Spec, rootPath, bundlePath, containerName string) (err error) {
 	var confwg sync.WaitGroup
 	config, conferr := json.Marshal(runUsingRuntimeSubprocOptions{
@@ -2178,9 +2339,30 @@ func (b *Builder) runUsingRuntimeSubproc(isolation define.Isolation, options Run
 	cmd.ExtraFiles = append([]*os.File{preader}, cmd.ExtraFiles...)
 	defer preader.Close()
 	defer pwriter.Close()
-	err = cmd.Run()
-	if err != nil {
-		err = errors.Wrapf(err, "error while running runtime")
+	b.recordRunState(options, containerName, bundlePath)
+	defer b.clearRunState()
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err = cmd.Start(); err != nil {
+		return errors.Wrapf(err, "error starting runtime")
+	}
+	waitErr := make(chan error, 1)
+	go func() {
+		waitErr <- cmd.Wait()
+	}()
+	select {
+	case err = <-waitErr:
+		if err != nil {
+			err = errors.Wrapf(err, "error while running runtime")
+		}
+	case <-ctx.Done():
+		if killErr := cmd.Process.Kill(); killErr != nil {
+			logrus.Infof("error killing runtime process after context was canceled: %v", killErr)
+		}
+		<-waitErr
+		err = ctx.Err()
 	}
 	confwg.Wait()
 	if err == nil {
@@ -1,3 +1,4 @@
+//go:build seccomp && linux
 // +build seccomp,linux
 
 package buildah
@@ -10,6 +11,12 @@ import (
 	"github.com/pkg/errors"
 )
 
+// SeccompEnabled returns true if this build of buildah was built with
+// seccomp support.
+func SeccompEnabled() bool {
+	return true
+}
+
 func setupSeccomp(spec *specs.Spec, seccompProfilePath string) error {
 	switch seccompProfilePath {
 	case "unconfined":
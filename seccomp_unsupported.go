@@ -1,3 +1,4 @@
+//go:build !seccomp || !linux
 // +build !seccomp !linux
 
 package buildah
@@ -6,6 +7,12 @@ import (
 	"github.com/opencontainers/runtime-spec/specs-go"
 )
 
+// SeccompEnabled returns true if this build of buildah was built with
+// seccomp support.
+func SeccompEnabled() bool {
+	return false
+}
+
 func setupSeccomp(spec *specs.Spec, seccompProfilePath string) error {
 	if spec.Linux != nil {
 		// runtime-tools may have supplied us with a default filter
@@ -0,0 +1,168 @@
+package buildah
+
+import (
+	"archive/tar"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/containers/storage"
+)
+
+// SizeEntry is a single path and the number of bytes attributed to it, used
+// to report the largest files or directories that make up an image.
+type SizeEntry struct {
+	Path string
+	Size int64
+}
+
+// LayerSizeBreakdown holds the largest files added or changed by a single
+// layer, in descending order by size.
+type LayerSizeBreakdown struct {
+	ID       string
+	TopFiles []SizeEntry
+}
+
+// ImageSizeBreakdown holds a per-layer and whole-image accounting of which
+// files and directories account for the most space in an image, as returned
+// by GetImageSizeBreakdown.
+type ImageSizeBreakdown struct {
+	PerLayer       []LayerSizeBreakdown
+	TopFiles       []SizeEntry
+	TopDirectories []SizeEntry
+}
+
+// GetImageSizeBreakdown walks the diff of each of the image's layers,
+// without mounting the image, to determine which files take up the most
+// space in each layer and in the image as a whole, and which directories
+// (accounting for files added, replaced, or removed by later layers) take
+// up the most space overall.  topN limits how many entries are kept in each
+// of the returned lists; a value of 0 or less means "no limit".
+func GetImageSizeBreakdown(store storage.Store, storeImage storage.Image, topN int) (*ImageSizeBreakdown, error) {
+	var layers []storage.Layer
+	layerID := storeImage.TopLayer
+	for layerID != "" {
+		layer, err := store.Layer(layerID)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, *layer)
+		layerID = layer.Parent
+	}
+
+	breakdown := &ImageSizeBreakdown{}
+	finalSizes := make(map[string]int64)
+
+	// Walk the layers oldest-first, so that later layers correctly
+	// override or remove files recorded by earlier ones when we compute
+	// the image's final, as-mounted contents.
+	for i := len(layers) - 1; i >= 0; i-- {
+		layer := layers[i]
+		layerSizes, err := diffFileSizes(store, layer.Parent, layer.ID)
+		if err != nil {
+			return nil, err
+		}
+		for name, size := range layerSizes {
+			if size < 0 {
+				delete(finalSizes, name)
+				continue
+			}
+			finalSizes[name] = size
+		}
+		breakdown.PerLayer = append(breakdown.PerLayer, LayerSizeBreakdown{
+			ID:       layer.ID,
+			TopFiles: topSizeEntries(layerSizes, topN),
+		})
+	}
+
+	dirSizes := make(map[string]int64)
+	for name, size := range finalSizes {
+		for _, dir := range ancestorDirs(name) {
+			dirSizes[dir] += size
+		}
+	}
+
+	breakdown.TopFiles = topSizeEntries(finalSizes, topN)
+	breakdown.TopDirectories = topSizeEntries(dirSizes, topN)
+
+	return breakdown, nil
+}
+
+// diffFileSizes reads the tar diff between two layers and returns a map of
+// the regular files it added or changed to their sizes, and the whiteouts
+// it recorded to a negative size, so that callers can tell removals from
+// additions without inspecting tar headers themselves.
+func diffFileSizes(store storage.Store, from, to string) (map[string]int64, error) {
+	diff, err := store.Diff(from, to, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer diff.Close()
+
+	sizes := make(map[string]int64)
+	tr := tar.NewReader(diff)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		name := path.Clean("/" + strings.TrimPrefix(hdr.Name, "./"))
+		dir, base := path.Split(name)
+		if strings.HasPrefix(base, ".wh.") {
+			if base == ".wh..wh..opq" {
+				// An opaque-directory marker hides everything that a
+				// lower layer may have placed under this directory;
+				// diffFileSizes only ever sees this one layer's own
+				// entries, so there's nothing more to do with it here.
+				continue
+			}
+			removed := path.Join(dir, strings.TrimPrefix(base, ".wh."))
+			sizes[removed] = -1
+			continue
+		}
+		if hdr.Typeflag == tar.TypeReg || hdr.Typeflag == tar.TypeRegA {
+			sizes[name] = hdr.Size
+		}
+	}
+	return sizes, nil
+}
+
+// ancestorDirs returns every directory, from the file's immediate parent up
+// to the root, that a path contributes its size to when computing
+// du(1)-style directory totals.
+func ancestorDirs(name string) []string {
+	var dirs []string
+	dir := path.Dir(name)
+	for {
+		dirs = append(dirs, dir)
+		if dir == "/" {
+			break
+		}
+		dir = path.Dir(dir)
+	}
+	return dirs
+}
+
+func topSizeEntries(sizes map[string]int64, topN int) []SizeEntry {
+	entries := make([]SizeEntry, 0, len(sizes))
+	for name, size := range sizes {
+		if size < 0 {
+			continue
+		}
+		entries = append(entries, SizeEntry{Path: name, Size: size})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Size != entries[j].Size {
+			return entries[i].Size > entries[j].Size
+		}
+		return entries[i].Path < entries[j].Path
+	})
+	if topN > 0 && len(entries) > topN {
+		entries = entries[:topN]
+	}
+	return entries
+}
@@ -0,0 +1,82 @@
+package buildah
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/buildah/copier"
+	"github.com/containers/storage/pkg/chrootarchive"
+	"github.com/pkg/errors"
+)
+
+// snapshotFile returns the path of the file in which we keep the archived
+// contents of a snapshot taken under the given name.
+func (b *Builder) snapshotFile(name string) string {
+	return "snapshot-" + name + ".tar"
+}
+
+// Snapshot archives the current contents of the container's root filesystem
+// under name, so that a later call to Restore() with the same name can put
+// the container's root filesystem back the way it was.  The container must
+// already be mounted.  Taking a snapshot replaces any snapshot which was
+// previously saved under the same name.
+func (b *Builder) Snapshot(name string) error {
+	if b.MountPoint == "" {
+		return errors.Errorf("error taking snapshot %q of container %q: container is not mounted", name, b.ContainerID)
+	}
+	cdir, err := b.store.ContainerDirectory(b.ContainerID)
+	if err != nil {
+		return err
+	}
+	archive, err := chrootarchive.Tar(b.MountPoint, nil, b.MountPoint)
+	if err != nil {
+		return errors.Wrapf(err, "error archiving container %q for snapshot %q", b.ContainerID, name)
+	}
+	defer archive.Close()
+	archivePath := filepath.Join(cdir, b.snapshotFile(name))
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return errors.Wrapf(err, "error creating snapshot %q of container %q", name, b.ContainerID)
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, archive); err != nil {
+		return errors.Wrapf(err, "error saving snapshot %q of container %q", name, b.ContainerID)
+	}
+	if b.Snapshots == nil {
+		b.Snapshots = make(map[string]struct{})
+	}
+	b.Snapshots[name] = struct{}{}
+	return b.Save()
+}
+
+// Restore replaces the contents of the container's root filesystem with the
+// contents that were saved under name by an earlier call to Snapshot().  The
+// container must already be mounted.  Any changes made to the root
+// filesystem since the snapshot was taken, including files that were added
+// after it was taken, are discarded.
+func (b *Builder) Restore(name string) error {
+	if b.MountPoint == "" {
+		return errors.Errorf("error restoring snapshot %q of container %q: container is not mounted", name, b.ContainerID)
+	}
+	if _, ok := b.Snapshots[name]; !ok {
+		return errors.Errorf("error restoring snapshot %q of container %q: no such snapshot", name, b.ContainerID)
+	}
+	cdir, err := b.store.ContainerDirectory(b.ContainerID)
+	if err != nil {
+		return err
+	}
+	archivePath := filepath.Join(cdir, b.snapshotFile(name))
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return errors.Wrapf(err, "error opening snapshot %q of container %q", name, b.ContainerID)
+	}
+	defer f.Close()
+	if err := copier.Remove(b.MountPoint, b.MountPoint, copier.RemoveOptions{All: true}); err != nil {
+		return errors.Wrapf(err, "error clearing container %q before restoring snapshot %q", b.ContainerID, name)
+	}
+	if err := chrootarchive.Untar(f, b.MountPoint, nil); err != nil {
+		return errors.Wrapf(err, "error extracting snapshot %q into container %q", name, b.ContainerID)
+	}
+	return nil
+}
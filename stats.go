@@ -0,0 +1,106 @@
+package buildah
+
+import (
+	"context"
+	"time"
+
+	"github.com/containers/image/v5/image"
+	"github.com/containers/image/v5/manifest"
+	is "github.com/containers/image/v5/storage"
+	"github.com/containers/image/v5/types"
+	"github.com/containers/storage"
+)
+
+// ImageStats holds the subset of an image's metadata that's expensive
+// enough to gather (it requires reading the image's manifest and walking
+// its layers) that callers doing this for many images at once are better
+// off sharing the work, which is what ImagesStats does.
+type ImageStats struct {
+	// Created is the image's creation time, per its config, if it could
+	// be determined.
+	Created time.Time
+	// TopLayerDigest is the digest of the image's manifest, which is
+	// also the value used to identify the image's topmost layer's
+	// contents in registries.
+	TopLayerDigest string
+	// Size is the uncompressed size, in bytes, of the image's layers.
+	// Layers shared between images in the same call to ImagesStats are
+	// only counted once per image, but each image's total does include
+	// every layer that makes it up.
+	Size int64
+	// LayerCount is the number of layers that make up the image.
+	LayerCount int
+	// Err is set if per-image information couldn't be gathered; the
+	// other fields should be ignored in that case.
+	Err error
+}
+
+// ImagesStats returns creation time, uncompressed size, layer count, and
+// manifest digest for each of the given images, sharing the computation of
+// each layer's uncompressed size across images that reference the same
+// layer, which is common for images that were built FROM one another or
+// that otherwise share a base.
+func ImagesStats(ctx context.Context, sys *types.SystemContext, store storage.Store, storeImages []storage.Image) ([]ImageStats, error) {
+	is.Transport.SetStore(store)
+	layerSizes := make(map[string]int64)
+	stats := make([]ImageStats, len(storeImages))
+	for i, storeImage := range storeImages {
+		stats[i] = imageStats(ctx, sys, store, storeImage, layerSizes)
+	}
+	return stats, nil
+}
+
+func imageStats(ctx context.Context, sys *types.SystemContext, store storage.Store, storeImage storage.Image, layerSizes map[string]int64) ImageStats {
+	var stats ImageStats
+
+	storeRef, err := is.Transport.ParseStoreReference(store, storeImage.ID)
+	if err != nil {
+		stats.Err = err
+		return stats
+	}
+	img, err := storeRef.NewImageSource(ctx, nil)
+	if err != nil {
+		stats.Err = err
+		return stats
+	}
+	defer img.Close()
+
+	manifestBytes, _, err := img.GetManifest(ctx, nil)
+	if err == nil && len(manifestBytes) > 0 {
+		if mDigest, digestErr := manifest.Digest(manifestBytes); digestErr == nil {
+			stats.TopLayerDigest = mDigest.String()
+		} else {
+			err = digestErr
+		}
+	}
+	if err != nil {
+		stats.Err = err
+		return stats
+	}
+
+	inspectable, err := image.FromUnparsedImage(ctx, sys, image.UnparsedInstance(img, nil))
+	if err == nil && inspectable != nil {
+		if inspectInfo, inspectErr := inspectable.Inspect(ctx); inspectErr == nil && inspectInfo != nil && inspectInfo.Created != nil {
+			stats.Created = *inspectInfo.Created
+		}
+	}
+
+	layerID := storeImage.TopLayer
+	for layerID != "" {
+		layer, layerErr := store.Layer(layerID)
+		if layerErr != nil {
+			stats.Err = layerErr
+			return stats
+		}
+		size, cached := layerSizes[layerID]
+		if !cached {
+			size = layer.UncompressedSize
+			layerSizes[layerID] = size
+		}
+		stats.Size += size
+		stats.LayerCount++
+		layerID = layer.Parent
+	}
+
+	return stats
+}
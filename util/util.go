@@ -10,6 +10,7 @@ import (
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/containers/buildah/define"
 	"github.com/containers/common/libimage"
@@ -154,6 +155,23 @@ func ExpandNames(names []string, systemContext *types.SystemContext, store stora
 	return expanded, nil
 }
 
+// findImageMaxRetries and findImageRetryDelay bound the number of times
+// FindImage will retry a local storage lookup that failed because another
+// process (for example, a concurrent "podman pull" of the same image) was
+// still writing to the same layer or image record when we tried to read it.
+const (
+	findImageMaxRetries = 3
+	findImageRetryDelay = 100 * time.Millisecond
+)
+
+// isStorageWriteConflict returns true for the errors that containers/storage
+// returns when a record it's asked to read is present but incomplete
+// because another process holding the store's lock is still writing it.
+func isStorageWriteConflict(err error) bool {
+	cause := errors.Cause(err)
+	return cause == storage.ErrLayerUnknown || cause == storage.ErrImageUnknown
+}
+
 // FindImage locates the locally-stored image which corresponds to a given name.
 // Please note that the `firstRegistry` argument has been deprecated and has no
 // effect anymore.
@@ -163,7 +181,15 @@ func FindImage(store storage.Store, firstRegistry string, systemContext *types.S
 		return nil, nil, err
 	}
 
-	localImage, _, err := runtime.LookupImage(image, nil)
+	var localImage *libimage.Image
+	for attempt := 0; ; attempt++ {
+		localImage, _, err = runtime.LookupImage(image, nil)
+		if err == nil || !isStorageWriteConflict(err) || attempt >= findImageMaxRetries {
+			break
+		}
+		logrus.Debugf("looking up image %q raced with a concurrent storage update (%v), retrying", image, err)
+		time.Sleep(findImageRetryDelay)
+	}
 	if err != nil {
 		return nil, nil, err
 	}